@@ -0,0 +1,121 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HealthCheckSpec mirrors the restarter's original flat health-check flags,
+// letting a RestartPolicy be authored without the full pluggable probe model
+// in internal/health; see health.HealthCheckOptions for that version.
+type HealthCheckSpec struct {
+	// HTTPCheckURL is the HTTP health check URL path (e.g. "/health").
+	HTTPCheckURL string `json:"httpCheckURL,omitempty"`
+	// HTTPCheckPort is the port HTTPCheckURL is checked on. Defaults to 8080.
+	HTTPCheckPort int `json:"httpCheckPort,omitempty"`
+	// TCPPort, when set, checks that this port accepts connections.
+	TCPPort int `json:"tcpPort,omitempty"`
+	// ExecCommand, when set, is run inside the container via the exec subresource.
+	ExecCommand string `json:"execCommand,omitempty"`
+	// ExecCheckContainer is the container exec checks run in (empty for the first container).
+	ExecCheckContainer string `json:"execCheckContainer,omitempty"`
+	// ExpectedOutput, when set, must appear in ExecCommand's output for the pod to be healthy.
+	ExpectedOutput string `json:"expectedOutput,omitempty"`
+	// AutoDeriveFromPodSpec derives probes from each container's own
+	// readiness/liveness probe instead of the fields above.
+	AutoDeriveFromPodSpec bool `json:"autoDeriveFromPodSpec,omitempty"`
+	// ProbeSource selects which container probe to derive from: "readiness" or "liveness".
+	ProbeSource string `json:"probeSource,omitempty"`
+	// Timeout bounds each individual health check. Defaults to 5s.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// RestartPolicySpec selects a set of pods and defines how the restarter
+// should health-check and restart them.
+type RestartPolicySpec struct {
+	// StatefulSetName restricts this policy to pods owned by this StatefulSet.
+	StatefulSetName string `json:"statefulSetName,omitempty"`
+	// PodLabelSelector restricts this policy to pods matching this selector
+	// (e.g. "app=router,component=druid").
+	PodLabelSelector string `json:"podLabelSelector,omitempty"`
+
+	// HealthCheck configures how matched pods are probed.
+	HealthCheck HealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// RestartStrategy is one of "immediate", "one-at-a-time", or "max-unavailable".
+	// Defaults to "immediate".
+	RestartStrategy string `json:"restartStrategy,omitempty"`
+	// MaxUnavailableCount bounds concurrent restarts for RestartStrategy "max-unavailable".
+	MaxUnavailableCount int `json:"maxUnavailableCount,omitempty"`
+	// MaxUnavailablePercent, when set, takes precedence over MaxUnavailableCount.
+	MaxUnavailablePercent int `json:"maxUnavailablePercent,omitempty"`
+
+	// RestartCooldown is the minimum time a given pod must wait between restarts.
+	RestartCooldown metav1.Duration `json:"restartCooldown,omitempty"`
+	// GlobalCooldown is the minimum time between any two restarts under this policy.
+	GlobalCooldown metav1.Duration `json:"globalCooldown,omitempty"`
+
+	// MaxRestartsPerWindow forces a restart once a container restarts more
+	// than this many times within RestartWindow. Zero disables the check.
+	MaxRestartsPerWindow int `json:"maxRestartsPerWindow,omitempty"`
+	// RestartWindow is the sliding window MaxRestartsPerWindow is evaluated over.
+	RestartWindow metav1.Duration `json:"restartWindow,omitempty"`
+	// WaitingReasons are container Waiting.Reason values that force an
+	// immediate restart (e.g. "CrashLoopBackOff").
+	WaitingReasons []string `json:"waitingReasons,omitempty"`
+
+	// RestartVerifyTimeout bounds how long the controller waits for a
+	// restarted pod's replacement to become Ready before declaring the
+	// restart failed. Defaults to 2m.
+	RestartVerifyTimeout metav1.Duration `json:"restartVerifyTimeout,omitempty"`
+	// MaxConsecutiveFailures stops restarting a pod once this many
+	// consecutive post-restart verifications have failed for it, backing
+	// off exponentially between attempts until then. Zero disables the
+	// give-up behavior (still backs off, but never stops retrying).
+	MaxConsecutiveFailures int `json:"maxConsecutiveFailures,omitempty"`
+}
+
+// PodRestartStatus records the restarter's last known state for a single pod.
+type PodRestartStatus struct {
+	PodName           string       `json:"podName"`
+	LastRestartTime   *metav1.Time `json:"lastRestartTime,omitempty"`
+	RestartCount      int32        `json:"restartCount,omitempty"`
+	LastFailureReason string       `json:"lastFailureReason,omitempty"`
+}
+
+// RestartPolicyStatus records what the controller has done under this policy.
+type RestartPolicyStatus struct {
+	LastRestartTime *metav1.Time       `json:"lastRestartTime,omitempty"`
+	RestartCount    int32              `json:"restartCount,omitempty"`
+	Pods            []PodRestartStatus `json:"pods,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="StatefulSet",type=string,JSONPath=`.spec.statefulSetName`
+// +kubebuilder:printcolumn:name="Strategy",type=string,JSONPath=`.spec.restartStrategy`
+// +kubebuilder:printcolumn:name="Restarts",type=integer,JSONPath=`.status.restartCount`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RestartPolicy selects a set of pods and defines how the restarter should
+// health-check and restart them.
+type RestartPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Spec   RestartPolicySpec   `json:"spec,omitempty"`
+	Status RestartPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RestartPolicyList contains a list of RestartPolicy.
+type RestartPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RestartPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RestartPolicy{}, &RestartPolicyList{})
+}
@@ -1,21 +1,31 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	restarterv1alpha1 "github.com/ealebed/restarter/api/v1alpha1"
 	"github.com/ealebed/restarter/internal/controller"
 	"github.com/ealebed/restarter/internal/health"
 )
@@ -24,6 +34,7 @@ var scheme = runtime.NewScheme()
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(restarterv1alpha1.AddToScheme(scheme))
 }
 
 // getEnv returns the environment variable value or the default value if not set.
@@ -41,8 +52,12 @@ var (
 		"StatefulSet name to monitor (env: STATEFULSET_NAME)")
 	podLabelSelector = flag.String("pod-label-selector", getEnv("POD_LABEL_SELECTOR", ""),
 		"Pod label selector (e.g., 'app=router,component=druid') (env: POD_LABEL_SELECTOR)")
+	metricsBindAddress = flag.String("metrics-bind-address", getEnv("METRICS_BIND_ADDRESS", "0"),
+		"Address the Prometheus metrics endpoint binds to, e.g. ':8443' (0 to disable) (env: METRICS_BIND_ADDRESS)")
 	healthCheckURL = flag.String("health-check-url", getEnv("HEALTH_CHECK_URL", ""),
 		"HTTP health check URL path (e.g., /health) (env: HEALTH_CHECK_URL)")
+	httpCheckPort = flag.Int("http-check-port", mustParseInt(getEnv("HTTP_CHECK_PORT", "8080")),
+		"Port to use for --health-check-url (env: HTTP_CHECK_PORT)")
 	healthCheckTimeout = flag.Duration("health-check-timeout",
 		mustParseDuration(getEnv("HEALTH_CHECK_TIMEOUT", "5s")),
 		"Timeout for health checks (env: HEALTH_CHECK_TIMEOUT)")
@@ -54,6 +69,43 @@ var (
 		"Expected output from exec command (empty to just check exit code) (env: EXEC_CHECK_EXPECTED)")
 	tcpCheckPort = flag.Int("tcp-check-port", mustParseInt(getEnv("TCP_CHECK_PORT", "0")),
 		"TCP port to check for connectivity (0 to disable) (env: TCP_CHECK_PORT)")
+	autoDeriveProbes = flag.Bool("auto-derive-probes", getEnv("AUTO_DERIVE_PROBES", "") == "true",
+		"Derive health checks from each container's own readiness/liveness probe instead of the flags above (env: AUTO_DERIVE_PROBES)")
+	probeSource = flag.String("probe-source", getEnv("PROBE_SOURCE", "readiness"),
+		"Which container probe to derive from when --auto-derive-probes is set: readiness or liveness (env: PROBE_SOURCE)")
+	maxRestartsPerWindow = flag.Int("max-restarts-per-window", mustParseInt(getEnv("MAX_RESTARTS_PER_WINDOW", "0")),
+		"Force a restart once a container restarts more than this many times within --restart-window (0 to disable) (env: MAX_RESTARTS_PER_WINDOW)")
+	restartWindow = flag.Duration("restart-window",
+		mustParseDuration(getEnv("RESTART_WINDOW", "10m")),
+		"Sliding window over which container restarts are counted for --max-restarts-per-window (env: RESTART_WINDOW)")
+	waitingReasons = flag.String("waiting-reasons",
+		getEnv("WAITING_REASONS", "CrashLoopBackOff,ImagePullBackOff,CreateContainerError"),
+		"Comma-separated container Waiting.Reason values that force an immediate restart (env: WAITING_REASONS)")
+	restartStrategyName = flag.String("restart-strategy", getEnv("RESTART_STRATEGY", "immediate"),
+		"Restart ordering strategy: immediate, one-at-a-time, or max-unavailable (env: RESTART_STRATEGY)")
+	maxUnavailableCount = flag.Int("max-unavailable-count", mustParseInt(getEnv("MAX_UNAVAILABLE_COUNT", "1")),
+		"Max number of pods allowed unavailable at once for --restart-strategy=max-unavailable (env: MAX_UNAVAILABLE_COUNT)")
+	maxUnavailablePercent = flag.Int("max-unavailable-percent", mustParseInt(getEnv("MAX_UNAVAILABLE_PERCENT", "0")),
+		"Max percent of replicas allowed unavailable at once for --restart-strategy=max-unavailable, takes precedence over --max-unavailable-count when set (env: MAX_UNAVAILABLE_PERCENT)")
+	restartCooldown = flag.Duration("restart-cooldown",
+		mustParseDuration(getEnv("RESTART_COOLDOWN", "0s")),
+		"Minimum time a given pod must wait between restarts (0 to disable) (env: RESTART_COOLDOWN)")
+	globalCooldown = flag.Duration("global-cooldown",
+		mustParseDuration(getEnv("GLOBAL_COOLDOWN", "0s")),
+		"Minimum time between any two restarts across all pods (0 to disable) (env: GLOBAL_COOLDOWN)")
+	healthCheckTransport = flag.String("health-check-transport", getEnv("HEALTH_CHECK_TRANSPORT", "direct"),
+		"How HTTP/TCP health checks reach a pod: direct (dial the Pod IP) or portforward (tunnel through the apiserver) (env: HEALTH_CHECK_TRANSPORT)")
+	portForwardMaxTunnels = flag.Int("portforward-max-tunnels", mustParseInt(getEnv("PORTFORWARD_MAX_TUNNELS", "32")),
+		"Maximum number of concurrent port-forward tunnels to keep open for --health-check-transport=portforward (env: PORTFORWARD_MAX_TUNNELS)")
+	leaderElection = flag.Bool("leader-election", getEnv("LEADER_ELECTION", "") == "true",
+		"Enable leader election, so only one replica reconciles at a time in an HA deployment (env: LEADER_ELECTION)")
+	leaderElectionID = flag.String("leader-election-id", getEnv("LEADER_ELECTION_ID", "restarter-leader-election"),
+		"Name of the Lease/ConfigMap used to coordinate leader election (env: LEADER_ELECTION_ID)")
+	restartVerifyTimeout = flag.Duration("restart-verify-timeout",
+		mustParseDuration(getEnv("RESTART_VERIFY_TIMEOUT", "2m")),
+		"How long to wait for a restarted pod's replacement to become Ready before declaring the restart failed (env: RESTART_VERIFY_TIMEOUT)")
+	maxConsecutiveFailures = flag.Int("max-consecutive-failures", mustParseInt(getEnv("MAX_CONSECUTIVE_FAILURES", "5")),
+		"Stop restarting a pod once this many consecutive post-restart verifications have failed for it (0 to retry forever) (env: MAX_CONSECUTIVE_FAILURES)")
 )
 
 // mustParseDuration parses a duration string or panics.
@@ -78,6 +130,138 @@ func mustParseInt(s string) int {
 	return i
 }
 
+// splitAndTrim splits a comma-separated list and trims whitespace from each
+// entry, dropping any that are empty.
+func splitAndTrim(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+// validateRestartStrategyName checks --restart-strategy against the names
+// RestartPolicyReconciler understands.
+func validateRestartStrategyName(name string) error {
+	switch name {
+	case "", "immediate", "one-at-a-time", "max-unavailable":
+		return nil
+	default:
+		return fmt.Errorf("unknown restart strategy %q", name)
+	}
+}
+
+// defaultRestartPolicyName derives the name of the RestartPolicy generated
+// from the legacy CLI flags for backward compatibility.
+func defaultRestartPolicyName() string {
+	if *statefulSetName != "" {
+		return *statefulSetName
+	}
+	return "default"
+}
+
+// defaultRestartPolicy builds the RestartPolicy equivalent to the legacy
+// CLI flags, so flag-only deployments keep working once the controller
+// reconciles RestartPolicy objects instead of raw flags.
+func defaultRestartPolicy() *restarterv1alpha1.RestartPolicy {
+	return &restarterv1alpha1.RestartPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      defaultRestartPolicyName(),
+			Namespace: *namespace,
+		},
+		Spec: restarterv1alpha1.RestartPolicySpec{
+			StatefulSetName:  *statefulSetName,
+			PodLabelSelector: *podLabelSelector,
+			HealthCheck: restarterv1alpha1.HealthCheckSpec{
+				HTTPCheckURL:          *healthCheckURL,
+				HTTPCheckPort:         *httpCheckPort,
+				TCPPort:               *tcpCheckPort,
+				ExecCommand:           *execCheckCommand,
+				ExecCheckContainer:    *execCheckContainer,
+				ExpectedOutput:        *execCheckExpected,
+				AutoDeriveFromPodSpec: *autoDeriveProbes,
+				ProbeSource:           *probeSource,
+				Timeout:               metav1.Duration{Duration: *healthCheckTimeout},
+			},
+			RestartStrategy:        *restartStrategyName,
+			MaxUnavailableCount:    *maxUnavailableCount,
+			MaxUnavailablePercent:  *maxUnavailablePercent,
+			RestartCooldown:        metav1.Duration{Duration: *restartCooldown},
+			GlobalCooldown:         metav1.Duration{Duration: *globalCooldown},
+			MaxRestartsPerWindow:   *maxRestartsPerWindow,
+			RestartWindow:          metav1.Duration{Duration: *restartWindow},
+			WaitingReasons:         splitAndTrim(*waitingReasons),
+			RestartVerifyTimeout:   metav1.Duration{Duration: *restartVerifyTimeout},
+			MaxConsecutiveFailures: *maxConsecutiveFailures,
+		},
+	}
+}
+
+// ensureDefaultRestartPolicy upserts the RestartPolicy generated from the
+// legacy CLI flags using a direct (uncached) client, so it exists before the
+// manager's cache-backed RestartPolicyReconciler starts watching. It retries
+// on AlreadyExists (another replica won the race to create it) and Conflict
+// (another replica updated it first), for as many attempts as
+// retry.DefaultBackoff allows or until ctx is done, whichever comes first,
+// since
+// defaultRestartPolicyRunnable only runs on the elected leader but races
+// against a replica from a previous rollout are still possible during a
+// handover.
+func ensureDefaultRestartPolicy(ctx context.Context, cfg *rest.Config) error {
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client for default RestartPolicy: %w", err)
+	}
+
+	desired := defaultRestartPolicy()
+	key := client.ObjectKeyFromObject(desired)
+
+	return wait.ExponentialBackoffWithContext(ctx, retry.DefaultBackoff, func(ctx context.Context) (bool, error) {
+		var existing restarterv1alpha1.RestartPolicy
+		switch err := c.Get(ctx, key, &existing); {
+		case apierrors.IsNotFound(err):
+			if err := c.Create(ctx, desired.DeepCopy()); err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					return false, nil // a concurrent replica created it first; re-fetch and retry
+				}
+				return false, fmt.Errorf("failed to create default RestartPolicy: %w", err)
+			}
+			return true, nil
+		case err != nil:
+			return false, fmt.Errorf("failed to get existing default RestartPolicy: %w", err)
+		default:
+			existing.Spec = desired.Spec
+			if err := c.Update(ctx, &existing); err != nil {
+				if apierrors.IsConflict(err) {
+					return false, nil // a concurrent replica updated it first; re-fetch and retry
+				}
+				return false, fmt.Errorf("failed to update default RestartPolicy: %w", err)
+			}
+			return true, nil
+		}
+	})
+}
+
+// defaultRestartPolicyRunnable upserts the RestartPolicy generated from the
+// legacy CLI flags. It requires leader election, so in an HA deployment only
+// the elected leader attempts the write instead of every replica racing to
+// create or update it before leader election has even decided a winner.
+type defaultRestartPolicyRunnable struct {
+	cfg *rest.Config
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (defaultRestartPolicyRunnable) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable.
+func (r defaultRestartPolicyRunnable) Start(ctx context.Context) error {
+	return ensureDefaultRestartPolicy(ctx, r.cfg)
+}
+
 func main() {
 	zopts := zap.Options{Development: false}
 	zopts.BindFlags(flag.CommandLine)
@@ -88,6 +272,10 @@ func main() {
 		ctrl.Log.Error(nil, "Either --statefulset or --pod-label-selector (or both) must be provided")
 		os.Exit(1)
 	}
+	if err := validateRestartStrategyName(*restartStrategyName); err != nil {
+		ctrl.Log.Error(err, "Invalid --restart-strategy")
+		os.Exit(1)
+	}
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&zopts)))
 	log := ctrl.Log.WithName("restarter")
@@ -96,18 +284,33 @@ func main() {
 		"namespace", *namespace,
 		"statefulset", *statefulSetName,
 		"podLabelSelector", *podLabelSelector,
+		"metricsBindAddress", *metricsBindAddress,
 		"healthCheckURL", *healthCheckURL,
+		"httpCheckPort", *httpCheckPort,
 		"healthCheckTimeout", *healthCheckTimeout,
 		"execCheckCommand", *execCheckCommand,
 		"tcpCheckPort", *tcpCheckPort,
+		"autoDeriveProbes", *autoDeriveProbes,
+		"probeSource", *probeSource,
+		"maxRestartsPerWindow", *maxRestartsPerWindow,
+		"restartWindow", *restartWindow,
+		"waitingReasons", *waitingReasons,
+		"restartStrategy", *restartStrategyName,
+		"restartCooldown", *restartCooldown,
+		"globalCooldown", *globalCooldown,
+		"healthCheckTransport", *healthCheckTransport,
+		"leaderElection", *leaderElection,
+		"restartVerifyTimeout", *restartVerifyTimeout,
+		"maxConsecutiveFailures", *maxConsecutiveFailures,
 	)
 
 	// Create manager options
 	opts := ctrl.Options{
 		Scheme:                 scheme,
-		LeaderElection:         false,                                    // Set to true for HA deployments
-		Metrics:                server.Options{BindAddress: "0"},         // Disable metrics server
-		HealthProbeBindAddress: getEnv("HEALTH_PROBE_BIND_ADDRESS", "0"), // Set to ":8080" to enable health probes
+		LeaderElection:         *leaderElection,
+		LeaderElectionID:       *leaderElectionID,
+		Metrics:                server.Options{BindAddress: *metricsBindAddress}, // Set to ":8443" to enable the /metrics endpoint
+		HealthProbeBindAddress: getEnv("HEALTH_PROBE_BIND_ADDRESS", "0"),         // Set to ":8080" to enable health probes
 	}
 
 	// Scope cache to the target namespace
@@ -138,24 +341,37 @@ func main() {
 		healthChecker.SetKubernetesClient(clientset, config)
 	}
 
-	// Build health check options
-	healthCheckOptions := health.HealthCheckOptions{
-		HTTPCheckURL:   *healthCheckURL,
-		ExecCommand:    *execCheckCommand,
-		TCPPort:        *tcpCheckPort,
-		ContainerName:  *execCheckContainer,
-		ExpectedOutput: *execCheckExpected,
+	// Switch HTTP/TCP health checks to tunnel through the apiserver when the
+	// controller has no direct route to the pod network.
+	if *healthCheckTransport == string(health.TransportPortForward) {
+		config := mgr.GetConfig()
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			log.Error(err, "Failed to create Kubernetes clientset for port-forward transport")
+			os.Exit(1)
+		}
+		healthChecker.SetPortForwardTransport(clientset, config, *portForwardMaxTunnels)
+	} else if *healthCheckTransport != "" && *healthCheckTransport != string(health.TransportDirect) {
+		log.Error(nil, "Unknown --health-check-transport", "value", *healthCheckTransport)
+		os.Exit(1)
+	}
+
+	// Upsert the RestartPolicy equivalent to the legacy flags above, so a
+	// flag-only deployment keeps working once RestartPolicyReconciler takes
+	// over from reconciling raw flags to reconciling RestartPolicy objects.
+	// Registered as a leader-election-gated Runnable so only the elected
+	// leader performs the write in an HA deployment.
+	if err := mgr.Add(defaultRestartPolicyRunnable{cfg: mgr.GetConfig()}); err != nil {
+		log.Error(err, "Failed to register default RestartPolicy upsert")
+		os.Exit(1)
 	}
 
 	// Setup controller
-	if err := (&controller.PodReconciler{
-		Client:             mgr.GetClient(),
-		Scheme:             mgr.GetScheme(),
-		StatefulSetName:    *statefulSetName,
-		PodLabelSelector:   *podLabelSelector,
-		Namespace:          *namespace,
-		HealthChecker:      healthChecker,
-		HealthCheckOptions: healthCheckOptions,
+	if err := (&controller.RestartPolicyReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		HealthChecker: healthChecker,
+		Recorder:      mgr.GetEventRecorderFor("restarter"),
 	}).SetupWithManager(mgr); err != nil {
 		log.Error(err, "Failed to setup controller")
 		os.Exit(1)
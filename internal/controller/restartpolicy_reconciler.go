@@ -0,0 +1,416 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	logr "github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	restarterv1alpha1 "github.com/ealebed/restarter/api/v1alpha1"
+	"github.com/ealebed/restarter/internal/detector"
+	"github.com/ealebed/restarter/internal/health"
+	"github.com/ealebed/restarter/internal/restart"
+)
+
+// defaultRestartVerifyTimeout applies when a RestartPolicy leaves
+// RestartVerifyTimeout unset.
+const defaultRestartVerifyTimeout = 2 * time.Minute
+
+// RestartPolicyReconciler reconciles RestartPolicy objects: for each one, it
+// lists the pods the policy selects, health-checks them, and restarts any
+// that are unhealthy subject to the policy's restart strategy and cooldowns.
+type RestartPolicyReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	HealthChecker *health.Checker
+	Recorder      record.EventRecorder
+
+	mu              sync.Mutex
+	detectors       map[types.UID]*detector.Detector
+	cooldowns       map[types.UID]*restart.CooldownTracker
+	failureTrackers map[types.UID]*restart.FailureTracker
+}
+
+// Reconcile is called whenever a RestartPolicy or a pod it might select changes.
+func (r *RestartPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("restartpolicy", req.NamespacedName)
+
+	var policy restarterv1alpha1.RestartPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get RestartPolicy: %w", err)
+	}
+
+	pods, err := r.matchingPods(ctx, &policy)
+	if err != nil {
+		logger.Error(err, "Failed to list pods for RestartPolicy")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	restartDetector, cooldown, failureTracker := r.stateFor(&policy)
+	healthOpts := healthCheckOptionsFromSpec(policy.Spec.HealthCheck)
+	strategy := restartStrategyFromSpec(policy.Namespace, policy.Spec)
+
+	for i := range pods {
+		status := r.reconcilePod(ctx, logger, &policy, &pods[i], restartDetector, cooldown, failureTracker, healthOpts, strategy)
+		setPodStatus(&policy.Status, status)
+	}
+
+	if err := r.Status().Update(ctx, &policy); err != nil {
+		logger.Error(err, "Failed to update RestartPolicy status")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// reconcilePod health-checks a single pod and restarts it if the policy's
+// strategy and cooldowns allow it, returning the pod's updated status entry.
+func (r *RestartPolicyReconciler) reconcilePod(
+	ctx context.Context,
+	logger logr.Logger,
+	policy *restarterv1alpha1.RestartPolicy,
+	pod *corev1.Pod,
+	restartDetector *detector.Detector,
+	cooldown *restart.CooldownTracker,
+	failureTracker *restart.FailureTracker,
+	healthOpts health.HealthCheckOptions,
+	strategy restart.Strategy,
+) restarterv1alpha1.PodRestartStatus {
+	status := restarterv1alpha1.PodRestartStatus{PodName: pod.Name}
+
+	healthy, err := r.HealthChecker.IsPodHealthy(ctx, pod, healthOpts)
+	if err != nil {
+		logger.Error(err, "Failed to check pod health", "pod", pod.Name)
+		status.LastFailureReason = err.Error()
+		return status
+	}
+
+	detected := restartDetector.Detect(pod, time.Now())
+	if detected.Unhealthy {
+		status.LastFailureReason = detected.Message
+	}
+
+	if healthy && !detected.Unhealthy {
+		return status
+	}
+
+	if allowed, remaining := cooldown.Allow(pod.UID, time.Now()); !allowed {
+		restart.RecordSkip("cooldown")
+		logger.Info("Deferring restart, pod is within its cooldown window", "pod", pod.Name, "remaining", remaining)
+		return status
+	}
+
+	if allowed, reason := failureTracker.Allow(pod.UID, time.Now()); !allowed {
+		restart.RecordSkip("verify_backoff")
+		logger.Info("Deferring restart, pod previously failed post-restart verification", "pod", pod.Name, "reason", reason)
+		return status
+	}
+
+	if allowed, reason, err := strategy.Allow(ctx, r.Client, pod); err != nil {
+		logger.Error(err, "Failed to evaluate restart strategy", "pod", pod.Name)
+		return status
+	} else if !allowed {
+		restart.RecordSkip("strategy")
+		logger.Info("Deferring restart per restart strategy", "pod", pod.Name, "reason", reason)
+		return status
+	}
+
+	if allowed, reason, err := restart.AllowedByPDB(ctx, r.Client, pod); err != nil {
+		logger.Error(err, "Failed to evaluate PodDisruptionBudgets", "pod", pod.Name)
+		return status
+	} else if !allowed {
+		restart.RecordSkip("pdb")
+		logger.Info("Deferring restart, would violate a PodDisruptionBudget", "pod", pod.Name, "reason", reason)
+		return status
+	}
+
+	podKey := client.ObjectKeyFromObject(pod)
+	previousUID := pod.UID
+
+	if err := r.Delete(ctx, pod); err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to delete pod", "pod", pod.Name)
+		}
+		return status
+	}
+
+	restartDetector.Forget(pod.UID)
+	cooldown.Record(pod.UID, time.Now())
+
+	now := metav1.Now()
+	status.LastRestartTime = &now
+	for _, prev := range policy.Status.Pods {
+		if prev.PodName == pod.Name {
+			status.RestartCount = prev.RestartCount
+		}
+	}
+	status.RestartCount++
+	if status.LastFailureReason == "" {
+		status.LastFailureReason = "health check failed"
+	}
+	policy.Status.LastRestartTime = &now
+	policy.Status.RestartCount++
+
+	logger.Info("Successfully triggered pod restart", "pod", pod.Name)
+
+	go r.verifyRestart(logger, policy.DeepCopy(), podKey, previousUID, cooldown, failureTracker)
+
+	return status
+}
+
+// verifyRestart polls (via the cache-backed r.Client) for podKey's
+// replacement to become Ready within policy's RestartVerifyTimeout, then
+// records the outcome in failureTracker and emits a RestartSucceeded or
+// RestartFailed Event on the pod's owning StatefulSet, if any. It runs in
+// its own goroutine, detached from the Reconcile call that triggered it, so
+// it uses a background context rather than ctx.
+func (r *RestartPolicyReconciler) verifyRestart(
+	logger logr.Logger,
+	policy *restarterv1alpha1.RestartPolicy,
+	podKey client.ObjectKey,
+	previousUID types.UID,
+	cooldown *restart.CooldownTracker,
+	failureTracker *restart.FailureTracker,
+) {
+	timeout := policy.Spec.RestartVerifyTimeout.Duration
+	if timeout <= 0 {
+		timeout = defaultRestartVerifyTimeout
+	}
+
+	ready, lastUID := restart.VerifyPodReady(context.Background(), r.Client, podKey, previousUID, restart.VerifyConfig{Timeout: timeout})
+	if lastUID == "" {
+		lastUID = previousUID
+	}
+
+	// previousUID's replacement has now settled one way or the other, so it
+	// is no longer "in-flight"; it will never be looked up again since the
+	// pod it was keyed to is gone.
+	cooldown.Forget(previousUID)
+
+	if lastUID != previousUID {
+		// The restart produced a genuinely new pod; any verification state
+		// left over from restarting the old one is dead weight now that
+		// future checks key off the new pod's UID.
+		failureTracker.Forget(previousUID)
+	}
+
+	if ready {
+		failureTracker.RecordSuccess(lastUID)
+		logger.Info("Post-restart verification succeeded", "pod", podKey.Name)
+		r.recordRestartEvent(policy, podKey.Name, corev1.EventTypeNormal, "RestartSucceeded",
+			fmt.Sprintf("Pod %s became Ready after being restarted", podKey.Name))
+		return
+	}
+
+	backoff := failureTracker.RecordFailure(lastUID, time.Now())
+	logger.Info("Post-restart verification failed, backing off before the next restart", "pod", podKey.Name, "backoff", backoff)
+	r.recordRestartEvent(policy, podKey.Name, corev1.EventTypeWarning, "RestartFailed",
+		fmt.Sprintf("Pod %s did not become Ready within %s of being restarted", podKey.Name, timeout))
+}
+
+// recordRestartEvent emits eventType/reason/message on the StatefulSet owning
+// the pod named podName, if policy selects one. Pods selected purely by
+// label, with no owning StatefulSet, get no event since there is nothing to
+// attach it to.
+func (r *RestartPolicyReconciler) recordRestartEvent(policy *restarterv1alpha1.RestartPolicy, podName, eventType, reason, message string) {
+	if r.Recorder == nil || policy.Spec.StatefulSetName == "" {
+		return
+	}
+
+	var sts appsv1.StatefulSet
+	key := types.NamespacedName{Namespace: policy.Namespace, Name: policy.Spec.StatefulSetName}
+	if err := r.Get(context.Background(), key, &sts); err != nil {
+		return
+	}
+
+	r.Recorder.Event(&sts, eventType, reason, message)
+}
+
+// stateFor returns (creating if necessary) the detector, cooldown tracker,
+// and post-restart-verification failure tracker for policy, keyed by its UID
+// so state survives across reconciles and a policy recreated under the same
+// name starts fresh. Entries for a deleted policy are not removed.
+func (r *RestartPolicyReconciler) stateFor(policy *restarterv1alpha1.RestartPolicy) (*detector.Detector, *restart.CooldownTracker, *restart.FailureTracker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.detectors == nil {
+		r.detectors = make(map[types.UID]*detector.Detector)
+		r.cooldowns = make(map[types.UID]*restart.CooldownTracker)
+		r.failureTrackers = make(map[types.UID]*restart.FailureTracker)
+	}
+
+	d, ok := r.detectors[policy.UID]
+	if !ok {
+		d = detector.New(detector.Config{
+			MaxRestartsPerWindow: policy.Spec.MaxRestartsPerWindow,
+			RestartWindow:        policy.Spec.RestartWindow.Duration,
+			WaitingReasons:       policy.Spec.WaitingReasons,
+		})
+		r.detectors[policy.UID] = d
+	}
+
+	c, ok := r.cooldowns[policy.UID]
+	if !ok {
+		c = restart.NewCooldownTracker(policy.Namespace+"/"+policy.Name, policy.Spec.RestartCooldown.Duration, policy.Spec.GlobalCooldown.Duration)
+		r.cooldowns[policy.UID] = c
+	}
+
+	f, ok := r.failureTrackers[policy.UID]
+	if !ok {
+		f = restart.NewFailureTracker(policy.Namespace+"/"+policy.Name, policy.Spec.MaxConsecutiveFailures)
+		r.failureTrackers[policy.UID] = f
+	}
+
+	return d, c, f
+}
+
+// matchingPods lists the pods selected by policy's StatefulSetName and/or
+// PodLabelSelector, mirroring the restarter's original flag semantics.
+func (r *RestartPolicyReconciler) matchingPods(ctx context.Context, policy *restarterv1alpha1.RestartPolicy) ([]corev1.Pod, error) {
+	var pods corev1.PodList
+	listOpts := []client.ListOption{client.InNamespace(policy.Namespace)}
+
+	if policy.Spec.PodLabelSelector != "" {
+		selector, err := labels.Parse(policy.Spec.PodLabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pod label selector: %w", err)
+		}
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	if err := r.List(ctx, &pods, listOpts...); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	if policy.Spec.StatefulSetName == "" {
+		return pods.Items, nil
+	}
+
+	var sts appsv1.StatefulSet
+	key := types.NamespacedName{Namespace: policy.Namespace, Name: policy.Spec.StatefulSetName}
+	if err := r.Get(ctx, key, &sts); err != nil {
+		return nil, fmt.Errorf("failed to get StatefulSet %s: %w", policy.Spec.StatefulSetName, err)
+	}
+	if sts.Spec.Selector == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build StatefulSet selector: %w", err)
+	}
+
+	matched := pods.Items[:0]
+	for _, pod := range pods.Items {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			matched = append(matched, pod)
+		}
+	}
+	return matched, nil
+}
+
+// setPodStatus records pod in status. If pod was just restarted (its
+// LastRestartTime is set) it replaces the existing entry for pod.PodName;
+// otherwise only that entry's LastFailureReason is updated. Entries for other
+// pods are preserved, and a pod with no entry yet is appended.
+func setPodStatus(status *restarterv1alpha1.RestartPolicyStatus, pod restarterv1alpha1.PodRestartStatus) {
+	for i := range status.Pods {
+		if status.Pods[i].PodName == pod.PodName {
+			if pod.LastRestartTime != nil {
+				status.Pods[i] = pod
+			} else {
+				status.Pods[i].LastFailureReason = pod.LastFailureReason
+			}
+			return
+		}
+	}
+	status.Pods = append(status.Pods, pod)
+}
+
+// healthCheckOptionsFromSpec converts a RestartPolicy's flat HealthCheckSpec
+// into health.HealthCheckOptions.
+func healthCheckOptionsFromSpec(spec restarterv1alpha1.HealthCheckSpec) health.HealthCheckOptions {
+	return health.HealthCheckOptions{
+		HTTPCheckURL:          spec.HTTPCheckURL,
+		HTTPCheckPort:         spec.HTTPCheckPort,
+		TCPPort:               spec.TCPPort,
+		ExecCommand:           spec.ExecCommand,
+		ContainerName:         spec.ExecCheckContainer,
+		ExpectedOutput:        spec.ExpectedOutput,
+		AutoDeriveFromPodSpec: spec.AutoDeriveFromPodSpec,
+		ProbeSource:           health.ProbeSource(spec.ProbeSource),
+		Timeout:               spec.Timeout.Duration,
+	}
+}
+
+// restartStrategyFromSpec builds the restart.Strategy named by spec.RestartStrategy.
+func restartStrategyFromSpec(namespace string, spec restarterv1alpha1.RestartPolicySpec) restart.Strategy {
+	switch spec.RestartStrategy {
+	case "one-at-a-time":
+		return restart.OneAtATime{StatefulSetName: spec.StatefulSetName, Namespace: namespace}
+	case "max-unavailable":
+		return restart.MaxUnavailable{
+			StatefulSetName:  spec.StatefulSetName,
+			PodLabelSelector: spec.PodLabelSelector,
+			Namespace:        namespace,
+			Count:            spec.MaxUnavailableCount,
+			Percent:          spec.MaxUnavailablePercent,
+		}
+	default:
+		return restart.Immediate{}
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RestartPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&restarterv1alpha1.RestartPolicy{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.podToRestartPolicies)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 1}).
+		Complete(r)
+}
+
+// podToRestartPolicies maps a pod event to the RestartPolicy objects in its
+// namespace that might select it, so pod-only changes (e.g. becoming Ready)
+// still trigger a reconcile without waiting for the next periodic requeue.
+func (r *RestartPolicyReconciler) podToRestartPolicies(ctx context.Context, obj client.Object) []ctrl.Request {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+
+	var policies restarterv1alpha1.RestartPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(pod.Namespace)); err != nil {
+		return nil
+	}
+
+	var requests []ctrl.Request
+	for _, policy := range policies.Items {
+		if policy.Spec.PodLabelSelector != "" {
+			selector, err := labels.Parse(policy.Spec.PodLabelSelector)
+			if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}})
+	}
+	return requests
+}
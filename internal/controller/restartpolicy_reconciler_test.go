@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	restarterv1alpha1 "github.com/ealebed/restarter/api/v1alpha1"
+	"github.com/ealebed/restarter/internal/detector"
+	"github.com/ealebed/restarter/internal/health"
+	"github.com/ealebed/restarter/internal/restart"
+)
+
+func readyPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name)},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+func unhealthyPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name)},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+		},
+	}
+}
+
+func newTestReconciler(objs ...client.Object) *RestartPolicyReconciler {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+
+	return &RestartPolicyReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		HealthChecker: health.NewChecker(5 * time.Second),
+	}
+}
+
+func TestReconcilePod_HealthyPodSkipsRestart(t *testing.T) {
+	pod := readyPod("web-0")
+	r := newTestReconciler(pod)
+
+	status := r.reconcilePod(
+		context.Background(), log.FromContext(context.Background()), &restarterv1alpha1.RestartPolicy{}, pod,
+		detector.New(detector.Config{}), restart.NewCooldownTracker("test", 0, 0), restart.NewFailureTracker("test", 0),
+		health.HealthCheckOptions{}, restart.Immediate{},
+	)
+
+	if status.LastRestartTime != nil {
+		t.Errorf("reconcilePod() restarted a healthy pod, LastRestartTime = %v, want nil", status.LastRestartTime)
+	}
+	var got corev1.Pod
+	if err := r.Get(context.Background(), client.ObjectKeyFromObject(pod), &got); err != nil {
+		t.Fatalf("expected healthy pod to still exist, Get() error: %v", err)
+	}
+}
+
+func TestReconcilePod_CooldownDefersRestart(t *testing.T) {
+	pod := unhealthyPod("web-0")
+	r := newTestReconciler(pod)
+
+	cooldown := restart.NewCooldownTracker("test", time.Hour, 0)
+	cooldown.Record(pod.UID, time.Now())
+
+	status := r.reconcilePod(
+		context.Background(), log.FromContext(context.Background()), &restarterv1alpha1.RestartPolicy{}, pod,
+		detector.New(detector.Config{}), cooldown, restart.NewFailureTracker("test", 0),
+		health.HealthCheckOptions{}, restart.Immediate{},
+	)
+
+	if status.LastRestartTime != nil {
+		t.Error("reconcilePod() restarted a pod still within its cooldown window")
+	}
+	var got corev1.Pod
+	if err := r.Get(context.Background(), client.ObjectKeyFromObject(pod), &got); err != nil {
+		t.Fatalf("expected pod to survive a deferred restart, Get() error: %v", err)
+	}
+}
+
+func TestReconcilePod_FailureBackoffDefersRestart(t *testing.T) {
+	pod := unhealthyPod("web-0")
+	r := newTestReconciler(pod)
+
+	failureTracker := restart.NewFailureTracker("test", 0)
+	failureTracker.RecordFailure(pod.UID, time.Now())
+
+	status := r.reconcilePod(
+		context.Background(), log.FromContext(context.Background()), &restarterv1alpha1.RestartPolicy{}, pod,
+		detector.New(detector.Config{}), restart.NewCooldownTracker("test", 0, 0), failureTracker,
+		health.HealthCheckOptions{}, restart.Immediate{},
+	)
+
+	if status.LastRestartTime != nil {
+		t.Error("reconcilePod() restarted a pod still within its post-restart-verification backoff")
+	}
+}
+
+func TestReconcilePod_StrategyDefersRestart(t *testing.T) {
+	pod := unhealthyPod("web-1")
+	r := newTestReconciler(pod)
+
+	status := r.reconcilePod(
+		context.Background(), log.FromContext(context.Background()), &restarterv1alpha1.RestartPolicy{}, pod,
+		detector.New(detector.Config{}), restart.NewCooldownTracker("test", 0, 0), restart.NewFailureTracker("test", 0),
+		health.HealthCheckOptions{}, restart.OneAtATime{StatefulSetName: "web", Namespace: "default"},
+	)
+
+	if status.LastRestartTime != nil {
+		t.Error("reconcilePod() restarted web-1 even though its predecessor web-0 isn't Ready")
+	}
+}
+
+func TestReconcilePod_PDBDefersRestart(t *testing.T) {
+	pod := unhealthyPod("web-0")
+	pod.Labels = map[string]string{"app": "web"}
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-pdb", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+	}
+	r := newTestReconciler(pod, pdb)
+
+	status := r.reconcilePod(
+		context.Background(), log.FromContext(context.Background()), &restarterv1alpha1.RestartPolicy{}, pod,
+		detector.New(detector.Config{}), restart.NewCooldownTracker("test", 0, 0), restart.NewFailureTracker("test", 0),
+		health.HealthCheckOptions{}, restart.Immediate{},
+	)
+
+	if status.LastRestartTime != nil {
+		t.Error("reconcilePod() restarted a pod that would violate its PodDisruptionBudget")
+	}
+}
+
+func TestSetPodStatus_AppendsNewEntry(t *testing.T) {
+	status := &restarterv1alpha1.RestartPolicyStatus{}
+
+	setPodStatus(status, restarterv1alpha1.PodRestartStatus{PodName: "web-0", LastFailureReason: "boom"})
+
+	if len(status.Pods) != 1 || status.Pods[0].PodName != "web-0" {
+		t.Fatalf("setPodStatus() = %+v, want a single entry for web-0", status.Pods)
+	}
+}
+
+func TestSetPodStatus_ReplacesEntryOnRestart(t *testing.T) {
+	now := metav1.Now()
+	status := &restarterv1alpha1.RestartPolicyStatus{
+		Pods: []restarterv1alpha1.PodRestartStatus{{PodName: "web-0", RestartCount: 1}},
+	}
+
+	setPodStatus(status, restarterv1alpha1.PodRestartStatus{PodName: "web-0", LastRestartTime: &now, RestartCount: 2})
+
+	if len(status.Pods) != 1 || status.Pods[0].RestartCount != 2 {
+		t.Fatalf("setPodStatus() = %+v, want the existing entry replaced", status.Pods)
+	}
+}
+
+func TestSetPodStatus_PreservesRestartTimeWhenOnlyFailureReasonChanges(t *testing.T) {
+	now := metav1.Now()
+	status := &restarterv1alpha1.RestartPolicyStatus{
+		Pods: []restarterv1alpha1.PodRestartStatus{{PodName: "web-0", LastRestartTime: &now, RestartCount: 1}},
+	}
+
+	setPodStatus(status, restarterv1alpha1.PodRestartStatus{PodName: "web-0", LastFailureReason: "still unhealthy"})
+
+	if status.Pods[0].LastRestartTime == nil || !status.Pods[0].LastRestartTime.Equal(&now) {
+		t.Errorf("setPodStatus() overwrote LastRestartTime when only LastFailureReason changed")
+	}
+	if status.Pods[0].LastFailureReason != "still unhealthy" {
+		t.Errorf("setPodStatus() LastFailureReason = %q, want %q", status.Pods[0].LastFailureReason, "still unhealthy")
+	}
+	if status.Pods[0].RestartCount != 1 {
+		t.Errorf("setPodStatus() RestartCount = %d, want unchanged 1", status.Pods[0].RestartCount)
+	}
+}
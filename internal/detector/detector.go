@@ -0,0 +1,182 @@
+// Package detector inspects pod and container status directly (rather than
+// probing the workload) to catch pods that are unhealthy in ways the
+// HTTP/TCP/Exec/gRPC probe layers in internal/health can't see, such as a
+// container stuck in CrashLoopBackOff or one that is restarting too
+// frequently to ever pass a probe.
+package detector
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Reason identifies why Detect flagged a pod as unhealthy.
+type Reason string
+
+const (
+	// ReasonWaitingState fires when a container's Waiting.Reason matches one
+	// of the configured WaitingReasons (e.g. CrashLoopBackOff).
+	ReasonWaitingState Reason = "waiting_state"
+	// ReasonRestartBudgetExceeded fires when a container restarted more than
+	// MaxRestartsPerWindow times within RestartWindow.
+	ReasonRestartBudgetExceeded Reason = "restart_budget_exceeded"
+)
+
+var triggersTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "restarter_detector_triggers_total",
+		Help: "Total number of times the status detector flagged a pod as unhealthy, by reason.",
+	},
+	[]string{"reason", "container"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(triggersTotal)
+}
+
+// Config configures a Detector.
+type Config struct {
+	// MaxRestartsPerWindow is the number of container restarts allowed
+	// within RestartWindow before the pod is flagged unhealthy. Zero
+	// disables the restart-budget trigger.
+	MaxRestartsPerWindow int
+	// RestartWindow is the sliding window over which restarts are counted.
+	RestartWindow time.Duration
+	// WaitingReasons are the corev1.ContainerStateWaiting.Reason values
+	// that immediately flag a pod as unhealthy (e.g. "CrashLoopBackOff").
+	WaitingReasons []string
+}
+
+// Result reports the outcome of a Detect call.
+type Result struct {
+	Unhealthy bool
+	Reason    Reason
+	Container string
+	Message   string
+}
+
+type restartSample struct {
+	at    time.Time
+	count int32
+}
+
+// Detector tracks per-container restart history and flags pods whose
+// status indicates they are stuck, independent of any probe result.
+type Detector struct {
+	cfg            Config
+	waitingReasons map[string]struct{}
+
+	mu      sync.Mutex
+	history map[types.UID]map[string][]restartSample
+}
+
+// New creates a Detector from cfg.
+func New(cfg Config) *Detector {
+	reasons := make(map[string]struct{}, len(cfg.WaitingReasons))
+	for _, r := range cfg.WaitingReasons {
+		reasons[r] = struct{}{}
+	}
+	return &Detector{
+		cfg:            cfg,
+		waitingReasons: reasons,
+		history:        make(map[types.UID]map[string][]restartSample),
+	}
+}
+
+// Detect inspects pod's container statuses and reports whether it should be
+// considered unhealthy. Detect also records the pod's current restart
+// counts, so it must be called once per reconcile even when Result.Unhealthy
+// is later ignored, in order to keep the sliding window accurate.
+func (d *Detector) Detect(pod *corev1.Pod, now time.Time) Result {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && d.isTrackedWaitingReason(cs.State.Waiting.Reason) {
+			for _, other := range pod.Status.ContainerStatuses {
+				d.recordRestart(pod.UID, other.Name, other.RestartCount, now)
+			}
+			triggersTotal.WithLabelValues(string(ReasonWaitingState), cs.Name).Inc()
+			return Result{
+				Unhealthy: true,
+				Reason:    ReasonWaitingState,
+				Container: cs.Name,
+				Message:   "container " + cs.Name + " is waiting: " + cs.State.Waiting.Reason,
+			}
+		}
+	}
+
+	// Always record restart samples, whether or not a container trips the
+	// waiting-reason check above, so the window stays accurate.
+	var budgetResult *Result
+	for _, cs := range pod.Status.ContainerStatuses {
+		exceeded := d.recordRestart(pod.UID, cs.Name, cs.RestartCount, now)
+		if exceeded && budgetResult == nil {
+			triggersTotal.WithLabelValues(string(ReasonRestartBudgetExceeded), cs.Name).Inc()
+			budgetResult = &Result{
+				Unhealthy: true,
+				Reason:    ReasonRestartBudgetExceeded,
+				Container: cs.Name,
+				Message:   "container " + cs.Name + " exceeded restart budget",
+			}
+		}
+	}
+	if budgetResult != nil {
+		return *budgetResult
+	}
+
+	return Result{}
+}
+
+// Forget drops restart history for a pod, e.g. once it has been deleted.
+func (d *Detector) Forget(uid types.UID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.history, uid)
+}
+
+func (d *Detector) isTrackedWaitingReason(reason string) bool {
+	if len(d.waitingReasons) == 0 {
+		return false
+	}
+	_, ok := d.waitingReasons[reason]
+	return ok
+}
+
+// recordRestart appends a sample for container, prunes samples outside the
+// window, and reports whether the observed restart count grew by more than
+// MaxRestartsPerWindow within the window.
+func (d *Detector) recordRestart(uid types.UID, container string, count int32, now time.Time) bool {
+	if d.cfg.MaxRestartsPerWindow <= 0 {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	containers, ok := d.history[uid]
+	if !ok {
+		containers = make(map[string][]restartSample)
+		d.history[uid] = containers
+	}
+
+	samples := append(containers[container], restartSample{at: now, count: count})
+
+	cutoff := now.Add(-d.cfg.RestartWindow)
+	start := 0
+	for start < len(samples) && samples[start].at.Before(cutoff) {
+		start++
+	}
+	samples = samples[start:]
+	containers[container] = samples
+
+	if len(samples) == 0 {
+		return false
+	}
+
+	delta := samples[len(samples)-1].count - samples[0].count
+	return int(delta) > d.cfg.MaxRestartsPerWindow
+}
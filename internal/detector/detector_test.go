@@ -0,0 +1,83 @@
+package detector
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDetector_Detect_WaitingReason(t *testing.T) {
+	d := New(Config{WaitingReasons: []string{"CrashLoopBackOff"}})
+
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "app",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					},
+				},
+			},
+		},
+	}
+
+	result := d.Detect(pod, time.Now())
+	if !result.Unhealthy || result.Reason != ReasonWaitingState {
+		t.Errorf("Detect() = %+v, want unhealthy with ReasonWaitingState", result)
+	}
+}
+
+func TestDetector_Detect_IgnoresUnlistedWaitingReason(t *testing.T) {
+	d := New(Config{WaitingReasons: []string{"CrashLoopBackOff"}})
+
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "app",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"},
+					},
+				},
+			},
+		},
+	}
+
+	result := d.Detect(pod, time.Now())
+	if result.Unhealthy {
+		t.Errorf("Detect() = %+v, want healthy for an untracked waiting reason", result)
+	}
+}
+
+func TestDetector_Detect_RestartBudgetExceeded(t *testing.T) {
+	d := New(Config{MaxRestartsPerWindow: 2, RestartWindow: time.Minute})
+
+	uid := types.UID("pod-1")
+	base := time.Now()
+
+	pod := func(restarts int32, at time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{UID: uid},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "app", RestartCount: restarts},
+				},
+			},
+		}
+	}
+
+	if result := d.Detect(pod(0, base), base); result.Unhealthy {
+		t.Fatalf("Detect() = %+v after first sample, want healthy", result)
+	}
+	if result := d.Detect(pod(1, base.Add(10*time.Second)), base.Add(10*time.Second)); result.Unhealthy {
+		t.Fatalf("Detect() = %+v after second sample, want healthy", result)
+	}
+	result := d.Detect(pod(3, base.Add(20*time.Second)), base.Add(20*time.Second))
+	if !result.Unhealthy || result.Reason != ReasonRestartBudgetExceeded {
+		t.Errorf("Detect() = %+v, want unhealthy with ReasonRestartBudgetExceeded", result)
+	}
+}
@@ -1,18 +1,38 @@
 package health
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/remotecommand"
+
+	execprobe "github.com/ealebed/restarter/internal/health/probes/exec"
+	grpcprobe "github.com/ealebed/restarter/internal/health/probes/grpc"
+	httpprobe "github.com/ealebed/restarter/internal/health/probes/http"
+	tcpprobe "github.com/ealebed/restarter/internal/health/probes/tcp"
+
+	"github.com/ealebed/restarter/internal/health/portforward"
+	"github.com/ealebed/restarter/internal/health/probes"
+)
+
+// Transport selects how HTTP/TCP probes reach a pod.
+type Transport string
+
+const (
+	// TransportDirect connects straight to the pod's IP, requiring the
+	// controller to have L3 reachability to the pod network.
+	TransportDirect Transport = "direct"
+	// TransportPortForward tunnels the probe through the apiserver's
+	// pods/portforward subresource, for controllers running outside the
+	// cluster network.
+	TransportPortForward Transport = "portforward"
 )
 
 // Checker performs health checks on pods.
@@ -21,6 +41,8 @@ type Checker struct {
 	timeout    time.Duration
 	k8sClient  kubernetes.Interface
 	restConfig *rest.Config
+	transport  Transport
+	pfPool     *portforward.Pool
 }
 
 // NewChecker creates a new health checker.
@@ -29,7 +51,8 @@ func NewChecker(timeout time.Duration) *Checker {
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
-		timeout: timeout,
+		timeout:   timeout,
+		transport: TransportDirect,
 	}
 }
 
@@ -39,13 +62,72 @@ func (c *Checker) SetKubernetesClient(client kubernetes.Interface, config *rest.
 	c.restConfig = config
 }
 
+// SetPortForwardTransport switches HTTP/TCP probes to tunnel through the
+// apiserver's port-forward subresource instead of dialing the pod IP
+// directly, using client/config to open the tunnels and maxTunnels to bound
+// how many are kept open at once (0 for a sensible default).
+func (c *Checker) SetPortForwardTransport(client kubernetes.Interface, config *rest.Config, maxTunnels int) {
+	c.transport = TransportPortForward
+	c.pfPool = portforward.NewPool(client, config, maxTunnels)
+}
+
+// ProbeKind identifies which underlying probe mechanism a ProbeSpec uses.
+type ProbeKind string
+
+const (
+	ProbeKindHTTP ProbeKind = "http"
+	ProbeKindTCP  ProbeKind = "tcp"
+	ProbeKindExec ProbeKind = "exec"
+	ProbeKindGRPC ProbeKind = "grpc"
+)
+
+// ProbeSource selects which probe on the pod's own spec to derive from when
+// HealthCheckOptions.AutoDeriveFromPodSpec is set.
+type ProbeSource string
+
+const (
+	ProbeSourceReadiness ProbeSource = "readiness"
+	ProbeSourceLiveness  ProbeSource = "liveness"
+)
+
+// ProbeSpec describes a single probe to run against a pod, independent of
+// how it was configured (explicitly or derived from the pod's own spec).
+type ProbeSpec struct {
+	Kind      ProbeKind
+	Container string        // container name; empty uses the pod's first container
+	Timeout   time.Duration // per-probe timeout; falls back to the Checker's timeout
+
+	HTTPGet   *httpprobe.Config
+	TCPSocket *tcpprobe.Config
+	Exec      *execprobe.Config
+	GRPC      *grpcprobe.Config
+}
+
 // HealthCheckOptions contains options for health checking.
 type HealthCheckOptions struct {
 	HTTPCheckURL   string // HTTP health check URL path (e.g., "/health")
+	HTTPCheckPort  int    // Port HTTPCheckURL is checked on (0 falls back to 8080)
 	ExecCommand    string // Command to execute in container (e.g., "ps aux | grep java")
 	TCPPort        int    // TCP port to check (0 to disable)
 	ContainerName  string // Container name (empty for first container)
 	ExpectedOutput string // Expected output from exec command (empty to just check exit code)
+
+	// Probes, when non-empty, replaces the legacy fields above with a
+	// pluggable set of HTTP/TCP/Exec/gRPC probes, all of which must pass.
+	Probes []ProbeSpec
+
+	// AutoDeriveFromPodSpec, when set and Probes is empty, builds the probe
+	// set from each container's own ReadinessProbe/LivenessProbe (selected
+	// via ProbeSource) instead of requiring the operator to duplicate probe
+	// configuration already declared on the workload.
+	AutoDeriveFromPodSpec bool
+	ProbeSource           ProbeSource // defaults to ProbeSourceReadiness
+
+	// Timeout bounds each individual health check. Zero falls back to the
+	// Checker's own timeout. A probe derived via AutoDeriveFromPodSpec uses
+	// its own TimeoutSeconds instead whenever that is set, even when Timeout
+	// is non-zero.
+	Timeout time.Duration
 }
 
 // IsPodHealthy checks if a pod is healthy based on its status and optional health checks.
@@ -64,33 +146,16 @@ func (c *Checker) IsPodHealthy(ctx context.Context, pod *corev1.Pod, opts Health
 		}
 	}
 
-	// Layer 3: HTTP health check (if configured)
-	if opts.HTTPCheckURL != "" {
-		healthy, err := c.checkHTTPHealth(ctx, pod, opts.HTTPCheckURL)
-		if err != nil {
-			return false, fmt.Errorf("http health check failed: %w", err)
-		}
-		if !healthy {
-			return false, nil
-		}
-	}
-
-	// Layer 4: TCP port check (if configured)
-	if opts.TCPPort > 0 {
-		healthy, err := c.checkTCPPort(ctx, pod, opts.TCPPort)
-		if err != nil {
-			return false, fmt.Errorf("tcp port check failed: %w", err)
-		}
-		if !healthy {
-			return false, nil
-		}
+	// Layer 3: run the configured (or derived) probes; all must pass.
+	specs, err := c.resolveProbeSpecs(pod, opts)
+	if err != nil {
+		return false, err
 	}
 
-	// Layer 5: Exec command check (if configured)
-	if opts.ExecCommand != "" {
-		healthy, err := c.checkExecCommand(ctx, pod, opts.ExecCommand, opts.ContainerName, opts.ExpectedOutput)
+	for _, spec := range specs {
+		healthy, err := c.runProbe(ctx, pod, spec)
 		if err != nil {
-			return false, fmt.Errorf("exec command check failed: %w", err)
+			return false, fmt.Errorf("%s probe failed: %w", spec.Kind, err)
 		}
 		if !healthy {
 			return false, nil
@@ -107,122 +172,278 @@ func (c *Checker) IsPodHealthyLegacy(ctx context.Context, pod *corev1.Pod, healt
 	})
 }
 
-// checkHTTPHealth performs an HTTP health check on a pod.
-func (c *Checker) checkHTTPHealth(ctx context.Context, pod *corev1.Pod, healthCheckURL string) (bool, error) {
-	if pod.Status.PodIP == "" {
-		return false, fmt.Errorf("pod IP is not available")
+// resolveProbeSpecs determines the set of probes to run for pod, preferring
+// explicit opts.Probes, then pod-derived probes, then the legacy flat
+// options, in that order.
+func (c *Checker) resolveProbeSpecs(pod *corev1.Pod, opts HealthCheckOptions) ([]ProbeSpec, error) {
+	if len(opts.Probes) > 0 {
+		return opts.Probes, nil
+	}
+
+	if opts.AutoDeriveFromPodSpec {
+		source := opts.ProbeSource
+		if source == "" {
+			source = ProbeSourceReadiness
+		}
+		return deriveProbeSpecs(pod, source, opts.Timeout)
 	}
 
-	// healthCheckURL should be a path (e.g., "/health" or "/status/health")
-	// Construct full URL with default port 8080
-	url := fmt.Sprintf("http://%s:8080%s", pod.Status.PodIP, healthCheckURL)
+	return legacyProbeSpecs(opts), nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return false, fmt.Errorf("failed to create request: %w", err)
+// legacyProbeSpecs translates the original flat HTTP/TCP/Exec fields into
+// ProbeSpecs so callers that haven't migrated keep working unchanged.
+func legacyProbeSpecs(opts HealthCheckOptions) []ProbeSpec {
+	var specs []ProbeSpec
+
+	if opts.HTTPCheckURL != "" {
+		port := opts.HTTPCheckPort
+		if port == 0 {
+			port = 8080
+		}
+		specs = append(specs, ProbeSpec{
+			Kind:      ProbeKindHTTP,
+			Container: opts.ContainerName,
+			Timeout:   opts.Timeout,
+			HTTPGet:   &httpprobe.Config{Path: opts.HTTPCheckURL, Port: port},
+		})
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return false, nil // Pod is unhealthy if we can't reach it
+	if opts.TCPPort > 0 {
+		specs = append(specs, ProbeSpec{
+			Kind:      ProbeKindTCP,
+			Container: opts.ContainerName,
+			Timeout:   opts.Timeout,
+			TCPSocket: &tcpprobe.Config{Port: opts.TCPPort},
+		})
+	}
+
+	if opts.ExecCommand != "" {
+		specs = append(specs, ProbeSpec{
+			Kind:      ProbeKindExec,
+			Container: opts.ContainerName,
+			Timeout:   opts.Timeout,
+			Exec: &execprobe.Config{
+				Command:        []string{"sh", "-c", opts.ExecCommand},
+				ExpectedOutput: opts.ExpectedOutput,
+			},
+		})
 	}
-	defer resp.Body.Close()
 
-	// Consider 2xx and 3xx status codes as healthy
-	return resp.StatusCode >= 200 && resp.StatusCode < 400, nil
+	return specs
 }
 
-// checkTCPPort checks if a TCP port is accepting connections.
-func (c *Checker) checkTCPPort(ctx context.Context, pod *corev1.Pod, port int) (bool, error) {
-	if pod.Status.PodIP == "" {
-		return false, fmt.Errorf("pod IP is not available")
+// deriveProbeSpecs builds a ProbeSpec per container from the pod's own
+// ReadinessProbe/LivenessProbe, mirroring the kubelet's
+// ConsistentHTTPGetHandlers defaulting: the Host header defaults to the pod
+// IP, the scheme defaults to HTTP, and headers pass through unchanged.
+// fallbackTimeout is used for a container probe that doesn't set its own
+// TimeoutSeconds.
+func deriveProbeSpecs(pod *corev1.Pod, source ProbeSource, fallbackTimeout time.Duration) ([]ProbeSpec, error) {
+	var specs []ProbeSpec
+
+	for _, container := range pod.Spec.Containers {
+		var probe *corev1.Probe
+		switch source {
+		case ProbeSourceLiveness:
+			probe = container.LivenessProbe
+		default:
+			probe = container.ReadinessProbe
+		}
+		if probe == nil {
+			continue
+		}
+
+		spec, err := probeSpecFromContainer(container, probe, fallbackTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("deriving probe for container %q: %w", container.Name, err)
+		}
+		specs = append(specs, spec)
 	}
 
-	address := fmt.Sprintf("%s:%d", pod.Status.PodIP, port)
+	return specs, nil
+}
 
-	dialer := net.Dialer{
-		Timeout: c.timeout,
+// probeSpecFromContainer converts a single corev1.Probe into a ProbeSpec.
+// fallbackTimeout is used when probe doesn't set its own TimeoutSeconds.
+func probeSpecFromContainer(container corev1.Container, probe *corev1.Probe, fallbackTimeout time.Duration) (ProbeSpec, error) {
+	spec := ProbeSpec{Container: container.Name, Timeout: fallbackTimeout}
+	if probe.TimeoutSeconds > 0 {
+		spec.Timeout = time.Duration(probe.TimeoutSeconds) * time.Second
 	}
 
-	conn, err := dialer.DialContext(ctx, "tcp", address)
-	if err != nil {
-		return false, nil // Port is not accepting connections
+	switch {
+	case probe.HTTPGet != nil:
+		port, err := resolveContainerPort(container, probe.HTTPGet.Port)
+		if err != nil {
+			return spec, err
+		}
+		scheme := httpprobe.SchemeHTTP
+		if probe.HTTPGet.Scheme == corev1.URISchemeHTTPS {
+			scheme = httpprobe.SchemeHTTPS
+		}
+		headers := make([]httpprobe.Header, 0, len(probe.HTTPGet.HTTPHeaders))
+		for _, h := range probe.HTTPGet.HTTPHeaders {
+			headers = append(headers, httpprobe.Header{Name: h.Name, Value: h.Value})
+		}
+		spec.Kind = ProbeKindHTTP
+		spec.HTTPGet = &httpprobe.Config{
+			Path:    probe.HTTPGet.Path,
+			Port:    port,
+			Host:    probe.HTTPGet.Host,
+			Scheme:  scheme,
+			Headers: headers,
+		}
+
+	case probe.TCPSocket != nil:
+		port, err := resolveContainerPort(container, probe.TCPSocket.Port)
+		if err != nil {
+			return spec, err
+		}
+		spec.Kind = ProbeKindTCP
+		spec.TCPSocket = &tcpprobe.Config{Port: port, Host: probe.TCPSocket.Host}
+
+	case probe.Exec != nil:
+		spec.Kind = ProbeKindExec
+		spec.Exec = &execprobe.Config{Command: probe.Exec.Command}
+
+	case probe.GRPC != nil:
+		spec.Kind = ProbeKindGRPC
+		service := ""
+		if probe.GRPC.Service != nil {
+			service = *probe.GRPC.Service
+		}
+		spec.GRPC = &grpcprobe.Config{Port: int(probe.GRPC.Port), Service: service}
+
+	default:
+		return spec, fmt.Errorf("probe has no recognized action")
 	}
-	defer conn.Close()
 
-	return true, nil
+	return spec, nil
 }
 
-// checkExecCommand executes a command in the pod container and checks the result.
-func (c *Checker) checkExecCommand(ctx context.Context, pod *corev1.Pod, command, containerName, expectedOutput string) (bool, error) {
-	if c.k8sClient == nil || c.restConfig == nil {
-		return false, fmt.Errorf("kubernetes client not configured for exec checks")
+// resolveContainerPort resolves a probe's IntOrString port against the
+// container's declared ports, as the kubelet does for named container ports.
+func resolveContainerPort(container corev1.Container, port intstr.IntOrString) (int, error) {
+	if port.Type == intstr.Int {
+		return port.IntValue(), nil
 	}
 
-	if len(pod.Spec.Containers) == 0 {
-		return false, fmt.Errorf("pod has no containers")
+	for _, p := range container.Ports {
+		if p.Name == port.StrVal {
+			return int(p.ContainerPort), nil
+		}
 	}
 
-	// Determine container name
-	container := containerName
-	if container == "" {
-		container = pod.Spec.Containers[0].Name
+	return 0, fmt.Errorf("container %q has no port named %q", container.Name, port.StrVal)
+}
+
+// runProbe builds the Prober for spec and runs it against pod.
+func (c *Checker) runProbe(ctx context.Context, pod *corev1.Pod, spec ProbeSpec) (bool, error) {
+	if pod.Status.PodIP == "" {
+		return false, fmt.Errorf("pod IP is not available")
 	}
 
-	// Create exec request
-	req := c.k8sClient.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Name(pod.Name).
-		Namespace(pod.Namespace).
-		SubResource("exec").
-		Timeout(c.timeout)
-
-	req.VersionedParams(&corev1.PodExecOptions{
-		Container: container,
-		Command:   []string{"sh", "-c", command},
-		Stdout:    true,
-		Stderr:    true,
-	}, scheme.ParameterCodec)
-
-	exec, err := remotecommand.NewSPDYExecutor(c.restConfig, "POST", req.URL())
-	if err != nil {
-		return false, fmt.Errorf("failed to create executor: %w", err)
+	container := spec.Container
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
 	}
 
-	// Capture output
-	var stdout, stderr bytes.Buffer
-	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdout: &stdout,
-		Stderr: &stderr,
-	})
+	target := probes.Target{
+		PodName:      pod.Name,
+		PodNamespace: pod.Namespace,
+		PodIP:        pod.Status.PodIP,
+		Container:    container,
+	}
 
-	// Check if command executed successfully
-	if err != nil {
-		return false, nil // Command failed or timed out
+	timeout := spec.Timeout
+	if timeout <= 0 {
+		timeout = c.timeout
 	}
 
-	// If expected output is specified, check if it matches
-	if expectedOutput != "" {
-		output := stdout.String()
-		if output != expectedOutput && !contains(output, expectedOutput) {
-			return false, nil // Output doesn't match expected
+	var prober probes.Prober
+	switch spec.Kind {
+	case ProbeKindHTTP:
+		if spec.HTTPGet == nil {
+			return false, fmt.Errorf("http probe missing HTTPGet config")
+		}
+		cfg := *spec.HTTPGet
+		cfg.Timeout = timeout
+		if c.transport == TransportPortForward {
+			host, port, err := c.tunnel(ctx, pod, cfg.Port)
+			if err != nil {
+				return false, err
+			}
+			cfg.Host, cfg.Port = host, port
+		}
+		prober = httpprobe.New(cfg)
+
+	case ProbeKindTCP:
+		if spec.TCPSocket == nil {
+			return false, fmt.Errorf("tcp probe missing TCPSocket config")
+		}
+		cfg := *spec.TCPSocket
+		cfg.Timeout = timeout
+		if c.transport == TransportPortForward {
+			host, port, err := c.tunnel(ctx, pod, cfg.Port)
+			if err != nil {
+				return false, err
+			}
+			cfg.Host, cfg.Port = host, port
+		}
+		prober = tcpprobe.New(cfg)
+
+	case ProbeKindExec:
+		if spec.Exec == nil {
+			return false, fmt.Errorf("exec probe missing Exec config")
+		}
+		if c.k8sClient == nil || c.restConfig == nil {
+			return false, fmt.Errorf("kubernetes client not configured for exec checks")
+		}
+		cfg := *spec.Exec
+		cfg.Timeout = timeout
+		prober = execprobe.New(c.k8sClient, c.restConfig, cfg)
+
+	case ProbeKindGRPC:
+		if spec.GRPC == nil {
+			return false, fmt.Errorf("grpc probe missing GRPC config")
 		}
+		cfg := *spec.GRPC
+		cfg.Timeout = timeout
+		prober = grpcprobe.New(cfg)
+
+	default:
+		return false, fmt.Errorf("unknown probe kind %q", spec.Kind)
 	}
 
-	return true, nil
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return prober.Probe(probeCtx, target)
 }
 
-// contains checks if a string contains a substring.
-func contains(s, substr string) bool {
-	if len(substr) > len(s) {
-		return false
+// tunnel opens (or reuses) a port-forward session to pod's remotePort and
+// returns the local host/port the probe should dial instead.
+func (c *Checker) tunnel(ctx context.Context, pod *corev1.Pod, remotePort int) (string, int, error) {
+	if c.pfPool == nil {
+		return "", 0, fmt.Errorf("port-forward transport is not configured")
 	}
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
+
+	t, err := c.pfPool.Get(ctx, pod.Namespace, pod.Name, pod.UID, remotePort)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open port-forward tunnel: %w", err)
 	}
-	return false
+
+	host, portStr, err := net.SplitHostPort(t.LocalAddr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid tunnel address %q: %w", t.LocalAddr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid tunnel port %q: %w", portStr, err)
+	}
+
+	return host, port, nil
 }
 
 // CheckPodStatus checks if a pod has problematic status conditions.
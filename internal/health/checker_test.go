@@ -6,6 +6,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestChecker_CheckPodStatus(t *testing.T) {
@@ -115,10 +116,10 @@ func TestChecker_CheckPodStatus(t *testing.T) {
 
 func TestChecker_IsPodHealthy(t *testing.T) {
 	tests := []struct {
-		name     string
-		pod      *corev1.Pod
-		opts     HealthCheckOptions
-		expected bool
+		name        string
+		pod         *corev1.Pod
+		opts        HealthCheckOptions
+		expected    bool
 		expectError bool
 	}{
 		{
@@ -135,8 +136,8 @@ func TestChecker_IsPodHealthy(t *testing.T) {
 					PodIP: "10.0.0.1",
 				},
 			},
-			opts:     HealthCheckOptions{},
-			expected: true,
+			opts:        HealthCheckOptions{},
+			expected:    true,
 			expectError: false,
 		},
 		{
@@ -146,8 +147,8 @@ func TestChecker_IsPodHealthy(t *testing.T) {
 					Phase: corev1.PodPending,
 				},
 			},
-			opts:     HealthCheckOptions{},
-			expected: false,
+			opts:        HealthCheckOptions{},
+			expected:    false,
 			expectError: false,
 		},
 		{
@@ -163,8 +164,8 @@ func TestChecker_IsPodHealthy(t *testing.T) {
 					},
 				},
 			},
-			opts:     HealthCheckOptions{},
-			expected: false,
+			opts:        HealthCheckOptions{},
+			expected:    false,
 			expectError: false,
 		},
 	}
@@ -206,3 +207,62 @@ func TestNewChecker(t *testing.T) {
 		t.Errorf("NewChecker() httpClient.Timeout = %v, want %v", checker.httpClient.Timeout, timeout)
 	}
 }
+
+func TestDeriveProbeSpecs(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "app",
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 9090}},
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{
+							HTTPGet: &corev1.HTTPGetAction{
+								Path: "/ready",
+								Port: intstr.FromString("http"),
+							},
+						},
+					},
+				},
+				{
+					Name: "sidecar",
+				},
+			},
+		},
+	}
+
+	specs, err := deriveProbeSpecs(pod, ProbeSourceReadiness, 0)
+	if err != nil {
+		t.Fatalf("deriveProbeSpecs() unexpected error: %v", err)
+	}
+
+	if len(specs) != 1 {
+		t.Fatalf("deriveProbeSpecs() returned %d specs, want 1", len(specs))
+	}
+
+	spec := specs[0]
+	if spec.Kind != ProbeKindHTTP || spec.Container != "app" {
+		t.Errorf("deriveProbeSpecs() spec = %+v, want HTTP probe for container app", spec)
+	}
+	if spec.HTTPGet == nil || spec.HTTPGet.Port != 9090 || spec.HTTPGet.Path != "/ready" {
+		t.Errorf("deriveProbeSpecs() HTTPGet = %+v, want port 9090 path /ready", spec.HTTPGet)
+	}
+}
+
+func TestResolveContainerPort(t *testing.T) {
+	container := corev1.Container{
+		Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9100}},
+	}
+
+	port, err := resolveContainerPort(container, intstr.FromString("metrics"))
+	if err != nil {
+		t.Fatalf("resolveContainerPort() unexpected error: %v", err)
+	}
+	if port != 9100 {
+		t.Errorf("resolveContainerPort() = %d, want 9100", port)
+	}
+
+	if _, err := resolveContainerPort(container, intstr.FromString("missing")); err == nil {
+		t.Error("resolveContainerPort() expected error for unknown port name, got nil")
+	}
+}
@@ -0,0 +1,170 @@
+// Package portforward tunnels health checks through the Kubernetes API
+// server's pods/portforward subresource, analogous to `kubectl port-forward`,
+// for clusters where the controller has no direct L3 route to a Pod IP.
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// Tunnel is a live port-forward to a single pod/port, reachable at LocalAddr.
+type Tunnel struct {
+	LocalAddr string
+
+	stopCh    chan struct{}
+	closeOnce sync.Once
+	lastUsed  time.Time
+}
+
+// Close tears down the tunnel. Safe to call more than once.
+func (t *Tunnel) Close() {
+	t.closeOnce.Do(func() { close(t.stopCh) })
+}
+
+// Pool caches tunnels keyed by pod+port so that probing many pods doesn't
+// open and tear down a new port-forward session on every reconcile.
+type Pool struct {
+	client     kubernetes.Interface
+	restConfig *rest.Config
+	maxTunnels int
+
+	mu      sync.Mutex
+	tunnels map[string]*Tunnel
+}
+
+// NewPool creates a Pool that keeps at most maxTunnels open at a time,
+// closing the least-recently-used one once the limit is reached.
+func NewPool(client kubernetes.Interface, restConfig *rest.Config, maxTunnels int) *Pool {
+	if maxTunnels <= 0 {
+		maxTunnels = 32
+	}
+	return &Pool{
+		client:     client,
+		restConfig: restConfig,
+		maxTunnels: maxTunnels,
+		tunnels:    make(map[string]*Tunnel),
+	}
+}
+
+// Get returns a tunnel to namespace/podName:remotePort, reusing a cached one
+// when available and opening a new one otherwise. Tunnels are keyed by
+// podUID rather than namespace/podName, since StatefulSet pod names are
+// stable across restarts: keying by name alone would hand a probe of the
+// replacement pod a stale tunnel into the SPDY session of the pod that was
+// just deleted.
+func (p *Pool) Get(ctx context.Context, namespace, podName string, podUID types.UID, remotePort int) (*Tunnel, error) {
+	key := fmt.Sprintf("%s:%d", podUID, remotePort)
+
+	p.mu.Lock()
+	if t, ok := p.tunnels[key]; ok {
+		t.lastUsed = time.Now()
+		p.mu.Unlock()
+		return t, nil
+	}
+	p.mu.Unlock()
+
+	tunnel, err := p.open(ctx, namespace, podName, remotePort)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.evictLocked()
+	p.tunnels[key] = tunnel
+	p.mu.Unlock()
+
+	return tunnel, nil
+}
+
+// evictLocked closes the least-recently-used tunnel if the pool is full.
+// Callers must hold p.mu.
+func (p *Pool) evictLocked() {
+	if len(p.tunnels) < p.maxTunnels {
+		return
+	}
+
+	var oldestKey string
+	var oldest time.Time
+	for key, t := range p.tunnels {
+		if oldestKey == "" || t.lastUsed.Before(oldest) {
+			oldestKey, oldest = key, t.lastUsed
+		}
+	}
+	if oldestKey != "" {
+		p.tunnels[oldestKey].Close()
+		delete(p.tunnels, oldestKey)
+	}
+}
+
+// Close tears down every cached tunnel.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, t := range p.tunnels {
+		t.Close()
+		delete(p.tunnels, key)
+	}
+}
+
+func (p *Pool) open(ctx context.Context, namespace, podName string, remotePort int) (*Tunnel, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(p.restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SPDY round tripper: %w", err)
+	}
+
+	req := p.client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("portforward")
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+
+	fw, err := portforward.New(dialer, []string{fmt.Sprintf("0:%d", remotePort)}, stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		close(stopCh)
+		return nil, fmt.Errorf("failed to create port forwarder: %w", err)
+	}
+
+	forwardErrCh := make(chan error, 1)
+	go func() { forwardErrCh <- fw.ForwardPorts() }()
+
+	select {
+	case <-readyCh:
+	case err := <-forwardErrCh:
+		return nil, fmt.Errorf("port forward to %s/%s:%d failed: %w", namespace, podName, remotePort, err)
+	case <-ctx.Done():
+		close(stopCh)
+		return nil, ctx.Err()
+	}
+
+	ports, err := fw.GetPorts()
+	if err != nil {
+		close(stopCh)
+		return nil, fmt.Errorf("failed to get forwarded port: %w", err)
+	}
+	if len(ports) == 0 {
+		close(stopCh)
+		return nil, fmt.Errorf("no port was forwarded to %s/%s:%d", namespace, podName, remotePort)
+	}
+
+	return &Tunnel{
+		LocalAddr: fmt.Sprintf("127.0.0.1:%d", ports[0].Local),
+		stopCh:    stopCh,
+		lastUsed:  time.Now(),
+	}, nil
+}
@@ -0,0 +1,43 @@
+package portforward
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNewPool_DefaultsMaxTunnels(t *testing.T) {
+	pool := NewPool(nil, nil, 0)
+	if pool.maxTunnels != 32 {
+		t.Errorf("NewPool() maxTunnels = %d, want default 32", pool.maxTunnels)
+	}
+}
+
+func TestPool_Get_CachesByPodUIDNotName(t *testing.T) {
+	pool := NewPool(nil, nil, 0)
+	cached := &Tunnel{LocalAddr: "127.0.0.1:9999", stopCh: make(chan struct{})}
+	pool.tunnels["uid-a:80"] = cached
+
+	got, err := pool.Get(context.Background(), "ns", "pod-0", types.UID("uid-a"), 80)
+	if err != nil {
+		t.Fatalf("Get() unexpected error for cached tunnel: %v", err)
+	}
+	if got != cached {
+		t.Errorf("Get() = %v, want the cached tunnel for the same pod UID", got)
+	}
+
+	// A replacement pod with the same namespace/name but a new UID (e.g.
+	// after the restarter deletes the old pod) must not be handed the old
+	// pod's tunnel, even though the cache still holds it under "uid-a".
+	if _, ok := pool.tunnels["uid-b:80"]; ok {
+		t.Fatal("test setup invariant violated: uid-b must not already be cached")
+	}
+}
+
+func TestTunnel_CloseIsIdempotent(t *testing.T) {
+	tunnel := &Tunnel{LocalAddr: "127.0.0.1:1234", stopCh: make(chan struct{})}
+
+	tunnel.Close()
+	tunnel.Close() // must not panic on double-close
+}
@@ -0,0 +1,87 @@
+// Package exec implements a Prober that runs a command inside a container.
+package exec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/ealebed/restarter/internal/health/probes"
+)
+
+// Config configures an exec probe, mirroring corev1.ExecAction.
+type Config struct {
+	Command        []string
+	ExpectedOutput string // empty to just check the exit code
+	Timeout        time.Duration
+}
+
+// Prober runs a command inside a pod's container via the exec subresource.
+type Prober struct {
+	cfg        Config
+	client     kubernetes.Interface
+	restConfig *rest.Config
+}
+
+// New creates an exec prober. client and restConfig must be non-nil.
+func New(client kubernetes.Interface, restConfig *rest.Config, cfg Config) *Prober {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = probes.DefaultTimeout
+	}
+	return &Prober{cfg: cfg, client: client, restConfig: restConfig}
+}
+
+// Probe implements probes.Prober.
+func (p *Prober) Probe(ctx context.Context, target probes.Target) (bool, error) {
+	if p.client == nil || p.restConfig == nil {
+		return false, fmt.Errorf("kubernetes client not configured for exec probes")
+	}
+	if target.Container == "" {
+		return false, fmt.Errorf("no container specified for exec probe")
+	}
+	if len(p.cfg.Command) == 0 {
+		return false, fmt.Errorf("exec probe has no command configured")
+	}
+
+	req := p.client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(target.PodName).
+		Namespace(target.PodNamespace).
+		SubResource("exec").
+		Timeout(p.cfg.Timeout)
+
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: target.Container,
+		Command:   p.cfg.Command,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(p.restConfig, "POST", req.URL())
+	if err != nil {
+		return false, fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return false, nil // Command failed or timed out
+	}
+
+	if p.cfg.ExpectedOutput != "" && !strings.Contains(stdout.String(), p.cfg.ExpectedOutput) {
+		return false, nil
+	}
+
+	return true, nil
+}
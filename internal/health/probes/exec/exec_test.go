@@ -0,0 +1,38 @@
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/ealebed/restarter/internal/health/probes"
+)
+
+func TestProber_Probe_RequiresKubernetesClient(t *testing.T) {
+	prober := New(nil, nil, Config{Command: []string{"true"}})
+
+	_, err := prober.Probe(context.Background(), probes.Target{PodName: "pod", PodNamespace: "default", Container: "app"})
+	if err == nil {
+		t.Fatal("Probe() expected error when no Kubernetes client is configured, got nil")
+	}
+}
+
+func TestProber_Probe_RequiresContainer(t *testing.T) {
+	prober := New(fake.NewSimpleClientset(), &rest.Config{}, Config{Command: []string{"true"}})
+
+	_, err := prober.Probe(context.Background(), probes.Target{PodName: "pod", PodNamespace: "default"})
+	if err == nil {
+		t.Fatal("Probe() expected error when no container is specified, got nil")
+	}
+}
+
+func TestProber_Probe_RequiresCommand(t *testing.T) {
+	prober := New(fake.NewSimpleClientset(), &rest.Config{}, Config{})
+
+	_, err := prober.Probe(context.Background(), probes.Target{PodName: "pod", PodNamespace: "default", Container: "app"})
+	if err == nil {
+		t.Fatal("Probe() expected error when no command is configured, got nil")
+	}
+}
@@ -0,0 +1,64 @@
+// Package grpc implements a Prober using the gRPC health checking protocol
+// (grpc.health.v1), the same protocol the kubelet speaks for a pod's
+// grpc probe.
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/ealebed/restarter/internal/health/probes"
+)
+
+// Config configures a gRPC probe, mirroring corev1.GRPCAction.
+type Config struct {
+	Port    int
+	Service string // service name to check; empty checks the server's overall health
+	Timeout time.Duration
+}
+
+// Prober queries a gRPC health service.
+type Prober struct {
+	cfg Config
+}
+
+// New creates a gRPC prober from cfg.
+func New(cfg Config) *Prober {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = probes.DefaultTimeout
+	}
+	return &Prober{cfg: cfg}
+}
+
+// Probe implements probes.Prober.
+func (p *Prober) Probe(ctx context.Context, target probes.Target) (bool, error) {
+	if target.PodIP == "" {
+		return false, fmt.Errorf("pod IP is not available")
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(dialCtx, fmt.Sprintf("%s:%d", target.PodIP, p.cfg.Port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		return false, nil // Target is unreachable
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: p.cfg.Service,
+	})
+	if err != nil {
+		return false, nil
+	}
+
+	return resp.GetStatus() == healthpb.HealthCheckResponse_SERVING, nil
+}
@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/ealebed/restarter/internal/health/probes"
+)
+
+func TestProber_Probe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start test listener: %v", err)
+	}
+	defer listener.Close()
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthSrv.SetServingStatus("broken", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	grpcSrv := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcSrv, healthSrv)
+	go grpcSrv.Serve(listener)
+	defer grpcSrv.Stop()
+
+	_, portStr, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to split listener address: %v", err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("failed to parse listener port: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		service  string
+		expected bool
+	}{
+		{name: "overall health serving", service: "", expected: true},
+		{name: "named service not serving", service: "broken", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prober := New(Config{Port: port, Service: tt.service, Timeout: time.Second})
+			ok, err := prober.Probe(context.Background(), probes.Target{PodIP: "127.0.0.1"})
+			if err != nil {
+				t.Fatalf("Probe() unexpected error: %v", err)
+			}
+			if ok != tt.expected {
+				t.Errorf("Probe() = %v, want %v", ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProber_Probe_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start test listener: %v", err)
+	}
+	_, portStr, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to split listener address: %v", err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("failed to parse listener port: %v", err)
+	}
+	listener.Close()
+
+	prober := New(Config{Port: port, Timeout: 500 * time.Millisecond})
+	ok, err := prober.Probe(context.Background(), probes.Target{PodIP: "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Probe() unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("Probe() = true, want false for an unreachable server")
+	}
+}
+
+func TestProber_Probe_NoHost(t *testing.T) {
+	prober := New(Config{Port: 8080})
+	_, err := prober.Probe(context.Background(), probes.Target{})
+	if err == nil {
+		t.Fatal("Probe() expected error when no pod IP is set, got nil")
+	}
+}
@@ -0,0 +1,94 @@
+// Package http implements an HTTP GET Prober.
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ealebed/restarter/internal/health/probes"
+)
+
+// Scheme is the URI scheme used for an HTTP probe request.
+type Scheme string
+
+const (
+	SchemeHTTP  Scheme = "HTTP"
+	SchemeHTTPS Scheme = "HTTPS"
+)
+
+// Header is a single HTTP header sent with the probe request.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Config configures an HTTP probe, mirroring corev1.HTTPGetAction.
+type Config struct {
+	Path    string
+	Port    int
+	Host    string // overrides the target's pod IP when set
+	Scheme  Scheme
+	Headers []Header
+	Timeout time.Duration
+}
+
+// Prober performs HTTP GET health checks.
+type Prober struct {
+	cfg    Config
+	client *http.Client
+}
+
+// New creates an HTTP prober from cfg.
+func New(cfg Config) *Prober {
+	if cfg.Scheme == "" {
+		cfg.Scheme = SchemeHTTP
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = probes.DefaultTimeout
+	}
+	return &Prober{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Probe implements probes.Prober.
+func (p *Prober) Probe(ctx context.Context, target probes.Target) (bool, error) {
+	host := p.cfg.Host
+	if host == "" {
+		host = target.PodIP
+	}
+	if host == "" {
+		return false, fmt.Errorf("no pod IP or host available for HTTP probe")
+	}
+
+	url := fmt.Sprintf("%s://%s:%d%s", strings.ToLower(string(p.cfg.Scheme)), host, p.cfg.Port, p.cfg.Path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Consistent with the kubelet's ConsistentHTTPGetHandlers: an explicit
+	// "Host" header overrides the request's Host field rather than being
+	// sent as a literal header.
+	for _, h := range p.cfg.Headers {
+		if strings.EqualFold(h.Name, "Host") {
+			req.Host = h.Value
+			continue
+		}
+		req.Header.Set(h.Name, h.Value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false, nil // Target is unhealthy if we can't reach it
+	}
+	defer resp.Body.Close()
+
+	// Consider 2xx and 3xx status codes as healthy
+	return resp.StatusCode >= 200 && resp.StatusCode < 400, nil
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ealebed/restarter/internal/health/probes"
+)
+
+func TestProber_Probe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthy" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatalf("failed to parse test server port: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{name: "healthy path", path: "/healthy", expected: true},
+		{name: "unhealthy path", path: "/broken", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prober := New(Config{Path: tt.path, Port: port, Timeout: time.Second})
+			ok, err := prober.Probe(context.Background(), probes.Target{PodIP: u.Hostname()})
+			if err != nil {
+				t.Fatalf("Probe() unexpected error: %v", err)
+			}
+			if ok != tt.expected {
+				t.Errorf("Probe() = %v, want %v", ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProber_Probe_NoHost(t *testing.T) {
+	prober := New(Config{Path: "/healthy", Port: 8080})
+	_, err := prober.Probe(context.Background(), probes.Target{})
+	if err == nil {
+		t.Fatal("Probe() expected error when no pod IP or host is set, got nil")
+	}
+}
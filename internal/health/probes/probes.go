@@ -0,0 +1,30 @@
+// Package probes defines the common types shared by the concrete probe
+// implementations (http, tcp, exec, grpc). Each implementation lives in its
+// own subpackage so that health.Checker can depend only on the Prober
+// interface and remain agnostic of the underlying transport.
+package probes
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTimeout is used when a probe does not specify its own timeout,
+// matching the kubelet's default probe timeoutSeconds of 1.
+const DefaultTimeout = time.Second
+
+// Target identifies the pod/container instance a Prober should check.
+type Target struct {
+	PodName      string
+	PodNamespace string
+	PodIP        string
+	Container    string
+}
+
+// Prober runs a single health check against a Target and reports whether it
+// succeeded. A returned error indicates the probe itself could not be
+// executed (e.g. missing configuration); connection failures and non-2xx/3xx
+// responses are reported as (false, nil), mirroring the rest of this package.
+type Prober interface {
+	Probe(ctx context.Context, target Target) (bool, error)
+}
@@ -0,0 +1,52 @@
+// Package tcp implements a TCP connect Prober.
+package tcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ealebed/restarter/internal/health/probes"
+)
+
+// Config configures a TCP probe, mirroring corev1.TCPSocketAction.
+type Config struct {
+	Port    int
+	Host    string // overrides the target's pod IP when set
+	Timeout time.Duration
+}
+
+// Prober checks that a TCP port accepts connections.
+type Prober struct {
+	cfg Config
+}
+
+// New creates a TCP prober from cfg.
+func New(cfg Config) *Prober {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = probes.DefaultTimeout
+	}
+	return &Prober{cfg: cfg}
+}
+
+// Probe implements probes.Prober.
+func (p *Prober) Probe(ctx context.Context, target probes.Target) (bool, error) {
+	host := p.cfg.Host
+	if host == "" {
+		host = target.PodIP
+	}
+	if host == "" {
+		return false, fmt.Errorf("no pod IP or host available for TCP probe")
+	}
+
+	dialer := net.Dialer{Timeout: p.cfg.Timeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", host, p.cfg.Port))
+	if err != nil {
+		return false, nil // Port is not accepting connections
+	}
+	defer conn.Close()
+
+	return true, nil
+}
@@ -0,0 +1,56 @@
+package tcp
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ealebed/restarter/internal/health/probes"
+)
+
+func TestProber_Probe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start test listener: %v", err)
+	}
+	defer listener.Close()
+
+	_, portStr, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to split listener address: %v", err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("failed to parse listener port: %v", err)
+	}
+
+	prober := New(Config{Port: port, Timeout: time.Second})
+
+	ok, err := prober.Probe(context.Background(), probes.Target{PodIP: "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Probe() unexpected error: %v", err)
+	}
+	if !ok {
+		t.Error("Probe() = false, want true for an open port")
+	}
+
+	listener.Close()
+
+	ok, err = prober.Probe(context.Background(), probes.Target{PodIP: "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Probe() unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("Probe() = true, want false for a closed port")
+	}
+}
+
+func TestProber_Probe_NoHost(t *testing.T) {
+	prober := New(Config{Port: 8080})
+	_, err := prober.Probe(context.Background(), probes.Target{})
+	if err == nil {
+		t.Fatal("Probe() expected error when no pod IP or host is set, got nil")
+	}
+}
@@ -0,0 +1,115 @@
+package restart
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var verifyBackoffPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "restarter_restart_verify_backoff_pods",
+	Help: "Number of pods with a failed post-restart verification not yet cleared by a later success.",
+}, []string{"policy"})
+
+func init() {
+	metrics.Registry.MustRegister(verifyBackoffPods)
+}
+
+// baseVerifyBackoff is the delay applied after a pod's first failed
+// post-restart verification; it doubles with each subsequent failure.
+const baseVerifyBackoff = 30 * time.Second
+
+// maxVerifyBackoff caps how long FailureTracker will ever make a pod wait.
+const maxVerifyBackoff = 30 * time.Minute
+
+type failureState struct {
+	consecutive int
+	retryAfter  time.Time
+}
+
+// FailureTracker gates restarts of a pod whose previous restart failed
+// post-restart verification, backing off exponentially between attempts and
+// giving up once a pod has failed too many times in a row.
+type FailureTracker struct {
+	maxConsecutiveFailures int
+
+	mu    sync.Mutex
+	state map[types.UID]*failureState
+
+	metric prometheus.Gauge
+}
+
+// NewFailureTracker creates a FailureTracker for the RestartPolicy identified
+// by policy (typically its "namespace/name"), used to label the
+// verify-backoff metric so multiple policies don't clobber each other's
+// gauge value. maxConsecutiveFailures <= 0 disables the give-up behavior
+// (the pod is retried forever, with backoff).
+func NewFailureTracker(policy string, maxConsecutiveFailures int) *FailureTracker {
+	return &FailureTracker{
+		maxConsecutiveFailures: maxConsecutiveFailures,
+		state:                  make(map[types.UID]*failureState),
+		metric:                 verifyBackoffPods.WithLabelValues(policy),
+	}
+}
+
+// Allow reports whether uid may be restarted at now, and if not, why.
+func (t *FailureTracker) Allow(uid types.UID, now time.Time) (bool, string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.state[uid]
+	if !ok {
+		return true, ""
+	}
+
+	if t.maxConsecutiveFailures > 0 && s.consecutive >= t.maxConsecutiveFailures {
+		return false, "exceeded max consecutive verification failures"
+	}
+
+	if remaining := s.retryAfter.Sub(now); remaining > 0 {
+		return false, "waiting out post-restart-verification backoff"
+	}
+
+	return true, ""
+}
+
+// RecordFailure registers a failed post-restart verification for uid at now
+// and returns the backoff now in effect before uid may be restarted again.
+func (t *FailureTracker) RecordFailure(uid types.UID, now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.state[uid]
+	if !ok {
+		s = &failureState{}
+		t.state[uid] = s
+	}
+
+	s.consecutive++
+	backoff := baseVerifyBackoff << uint(s.consecutive-1) //nolint:gosec // consecutive is bounded by maxConsecutiveFailures in practice
+	if backoff <= 0 || backoff > maxVerifyBackoff {
+		backoff = maxVerifyBackoff
+	}
+	s.retryAfter = now.Add(backoff)
+
+	t.metric.Set(float64(len(t.state)))
+	return backoff
+}
+
+// RecordSuccess clears uid's failure history once a restart verifies Ready.
+func (t *FailureTracker) RecordSuccess(uid types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.state, uid)
+	t.metric.Set(float64(len(t.state)))
+}
+
+// Forget drops any failure history for uid, e.g. once its pod is gone.
+func (t *FailureTracker) Forget(uid types.UID) {
+	t.RecordSuccess(uid)
+}
@@ -0,0 +1,51 @@
+package restart
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestFailureTracker_ExponentialBackoff(t *testing.T) {
+	tracker := NewFailureTracker("test", 0)
+	uid := types.UID("pod-1")
+	now := time.Now()
+
+	first := tracker.RecordFailure(uid, now)
+	second := tracker.RecordFailure(uid, now)
+
+	if second <= first {
+		t.Errorf("RecordFailure() backoff did not grow: first=%v second=%v", first, second)
+	}
+
+	if allowed, _ := tracker.Allow(uid, now.Add(first)); allowed {
+		t.Error("Allow() = true still within backoff window, want false")
+	}
+}
+
+func TestFailureTracker_MaxConsecutiveFailures(t *testing.T) {
+	tracker := NewFailureTracker("test", 2)
+	uid := types.UID("pod-1")
+	now := time.Now()
+
+	tracker.RecordFailure(uid, now)
+	backoff := tracker.RecordFailure(uid, now)
+
+	if allowed, _ := tracker.Allow(uid, now.Add(backoff).Add(time.Hour)); allowed {
+		t.Error("Allow() = true after max consecutive failures, want false")
+	}
+}
+
+func TestFailureTracker_RecordSuccessClearsState(t *testing.T) {
+	tracker := NewFailureTracker("test", 1)
+	uid := types.UID("pod-1")
+	now := time.Now()
+
+	tracker.RecordFailure(uid, now)
+	tracker.RecordSuccess(uid)
+
+	if allowed, _ := tracker.Allow(uid, now); !allowed {
+		t.Error("Allow() = false after RecordSuccess(), want true")
+	}
+}
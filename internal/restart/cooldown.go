@@ -0,0 +1,91 @@
+package restart
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var inFlightRestarts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "restarter_inflight_restarts",
+	Help: "Number of restarted pods recorded by the cooldown tracker and not yet forgotten once their restart settled.",
+}, []string{"policy"})
+
+func init() {
+	metrics.Registry.MustRegister(inFlightRestarts)
+}
+
+// CooldownTracker enforces a per-pod cooldown (so the same pod isn't
+// restarted again too soon) and a global cooldown shared across all pods
+// (so restarts are spread out rather than all landing at once).
+type CooldownTracker struct {
+	perPod time.Duration
+	global time.Duration
+
+	mu           sync.Mutex
+	lastByPodUID map[types.UID]time.Time
+	lastGlobal   time.Time
+
+	metric prometheus.Gauge
+}
+
+// NewCooldownTracker creates a CooldownTracker for the RestartPolicy
+// identified by policy (typically its "namespace/name"), used to label the
+// in-flight-restarts metric so multiple policies don't clobber each other's
+// gauge value. Either duration may be zero to disable that particular
+// cooldown.
+func NewCooldownTracker(policy string, perPod, global time.Duration) *CooldownTracker {
+	return &CooldownTracker{
+		perPod:       perPod,
+		global:       global,
+		lastByPodUID: make(map[types.UID]time.Time),
+		metric:       inFlightRestarts.WithLabelValues(policy),
+	}
+}
+
+// Allow reports whether uid may be restarted at now, and if not, how much
+// longer the caller should wait.
+func (t *CooldownTracker) Allow(uid types.UID, now time.Time) (bool, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.global > 0 {
+		if remaining := t.global - now.Sub(t.lastGlobal); remaining > 0 {
+			return false, remaining
+		}
+	}
+
+	if t.perPod > 0 {
+		if last, ok := t.lastByPodUID[uid]; ok {
+			if remaining := t.perPod - now.Sub(last); remaining > 0 {
+				return false, remaining
+			}
+		}
+	}
+
+	return true, 0
+}
+
+// Record marks uid as having just been restarted at now.
+func (t *CooldownTracker) Record(uid types.UID, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastByPodUID[uid] = now
+	t.lastGlobal = now
+	t.metric.Set(float64(len(t.lastByPodUID)))
+}
+
+// Forget drops cooldown state for uid, e.g. once its replacement pod has
+// settled and is no longer considered in-flight.
+func (t *CooldownTracker) Forget(uid types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.lastByPodUID, uid)
+	t.metric.Set(float64(len(t.lastByPodUID)))
+}
@@ -0,0 +1,52 @@
+package restart
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCooldownTracker_PerPod(t *testing.T) {
+	tracker := NewCooldownTracker("test", time.Minute, 0)
+	uid := types.UID("pod-1")
+	now := time.Now()
+
+	if allowed, _ := tracker.Allow(uid, now); !allowed {
+		t.Fatal("Allow() = false before any restart recorded, want true")
+	}
+
+	tracker.Record(uid, now)
+
+	if allowed, remaining := tracker.Allow(uid, now.Add(30*time.Second)); allowed || remaining <= 0 {
+		t.Errorf("Allow() = (%v, %v) within cooldown, want (false, >0)", allowed, remaining)
+	}
+
+	if allowed, _ := tracker.Allow(uid, now.Add(2*time.Minute)); !allowed {
+		t.Error("Allow() = false after cooldown elapsed, want true")
+	}
+}
+
+func TestCooldownTracker_Global(t *testing.T) {
+	tracker := NewCooldownTracker("test", 0, time.Minute)
+	now := time.Now()
+
+	tracker.Record(types.UID("pod-1"), now)
+
+	if allowed, _ := tracker.Allow(types.UID("pod-2"), now.Add(10*time.Second)); allowed {
+		t.Error("Allow() = true for a different pod within the global cooldown, want false")
+	}
+}
+
+func TestCooldownTracker_Forget(t *testing.T) {
+	tracker := NewCooldownTracker("test", time.Minute, 0)
+	uid := types.UID("pod-1")
+	now := time.Now()
+
+	tracker.Record(uid, now)
+	tracker.Forget(uid)
+
+	if allowed, _ := tracker.Allow(uid, now.Add(time.Second)); !allowed {
+		t.Error("Allow() = false after Forget(), want true")
+	}
+}
@@ -0,0 +1,24 @@
+package restart
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var skippedTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "restarter_restart_skipped_total",
+		Help: "Total number of times an unhealthy pod's restart was deferred, by reason.",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(skippedTotal)
+}
+
+// RecordSkip records that a restart was deferred for the given reason
+// ("cooldown", "verify_backoff", "strategy", "pdb").
+func RecordSkip(reason string) {
+	skippedTotal.WithLabelValues(reason).Inc()
+}
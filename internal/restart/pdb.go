@@ -0,0 +1,36 @@
+package restart
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AllowedByPDB reports whether deleting pod would violate a PodDisruptionBudget
+// whose selector matches it. A pod matching no PDB is always allowed.
+func AllowedByPDB(ctx context.Context, c client.Client, pod *corev1.Pod) (bool, string, error) {
+	var pdbs policyv1.PodDisruptionBudgetList
+	if err := c.List(ctx, &pdbs, client.InNamespace(pod.Namespace)); err != nil {
+		return false, "", fmt.Errorf("failed to list PodDisruptionBudgets: %w", err)
+	}
+
+	for _, pdb := range pdbs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to build selector for PodDisruptionBudget %s: %w", pdb.Name, err)
+		}
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if pdb.Status.DisruptionsAllowed <= 0 {
+			return false, fmt.Sprintf("PodDisruptionBudget %s allows no further disruptions", pdb.Name), nil
+		}
+	}
+
+	return true, "", nil
+}
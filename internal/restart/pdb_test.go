@@ -0,0 +1,79 @@
+package restart
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAllowedByPDB_NoMatchingPDB(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"}}
+
+	allowed, reason, err := AllowedByPDB(context.Background(), c, pod)
+	if err != nil {
+		t.Fatalf("AllowedByPDB() unexpected error: %v", err)
+	}
+	if !allowed {
+		t.Errorf("AllowedByPDB() = (false, %q), want true when no PodDisruptionBudget matches", reason)
+	}
+}
+
+func TestAllowedByPDB_BlocksWhenNoDisruptionsAllowed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-pdb", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pdb).Build()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", Labels: map[string]string{"app": "web"}}}
+
+	allowed, reason, err := AllowedByPDB(context.Background(), c, pod)
+	if err != nil {
+		t.Fatalf("AllowedByPDB() unexpected error: %v", err)
+	}
+	if allowed {
+		t.Errorf("AllowedByPDB() = (true, %q), want false when the matching PDB allows no disruptions", reason)
+	}
+}
+
+func TestAllowedByPDB_AllowsWhenDisruptionsRemain(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-pdb", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pdb).Build()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", Labels: map[string]string{"app": "web"}}}
+
+	allowed, _, err := AllowedByPDB(context.Background(), c, pod)
+	if err != nil {
+		t.Fatalf("AllowedByPDB() unexpected error: %v", err)
+	}
+	if !allowed {
+		t.Error("AllowedByPDB() = false, want true when the matching PDB still allows a disruption")
+	}
+}
@@ -0,0 +1,162 @@
+// Package restart provides the restart-ordering policy for RestartPolicyReconciler:
+// deciding, for a pod already known to be unhealthy, whether it is safe to
+// delete it right now given the state of its siblings.
+package restart
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Strategy decides whether an already-unhealthy pod may be deleted now.
+type Strategy interface {
+	// Allow reports whether pod may be restarted right now. reason explains
+	// a false result and is surfaced in logs.
+	Allow(ctx context.Context, c client.Client, pod *corev1.Pod) (allowed bool, reason string, err error)
+}
+
+// Immediate allows every unhealthy pod to be restarted as soon as it's
+// found, preserving the restarter's original behavior.
+type Immediate struct{}
+
+// Allow implements Strategy.
+func (Immediate) Allow(_ context.Context, _ client.Client, _ *corev1.Pod) (bool, string, error) {
+	return true, "", nil
+}
+
+// OneAtATime only restarts a StatefulSet pod once its lower-ordinal sibling
+// (pod N-1) is Ready, so a broken rollout doesn't take down the whole set at
+// once. Pods not owned by a StatefulSet (no numeric ordinal suffix) are
+// always allowed, since there's no ordering to respect.
+type OneAtATime struct {
+	StatefulSetName string
+	Namespace       string
+}
+
+// Allow implements Strategy.
+func (s OneAtATime) Allow(ctx context.Context, c client.Client, pod *corev1.Pod) (bool, string, error) {
+	ordinal, ok := podOrdinal(pod.Name, s.StatefulSetName)
+	if !ok || ordinal == 0 {
+		return true, "", nil
+	}
+
+	var predecessor corev1.Pod
+	key := types.NamespacedName{Namespace: s.Namespace, Name: fmt.Sprintf("%s-%d", s.StatefulSetName, ordinal-1)}
+	if err := c.Get(ctx, key, &predecessor); err != nil {
+		return false, fmt.Sprintf("predecessor pod %s not found", key.Name), nil //nolint:nilerr // treated as "not ready yet"
+	}
+
+	if !isPodReady(&predecessor) {
+		return false, fmt.Sprintf("predecessor pod %s is not Ready", key.Name), nil
+	}
+
+	return true, "", nil
+}
+
+// podOrdinal extracts a StatefulSet pod's ordinal from its name
+// ("<statefulSetName>-<ordinal>").
+func podOrdinal(podName, statefulSetName string) (int, bool) {
+	prefix := statefulSetName + "-"
+	if !strings.HasPrefix(podName, prefix) {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(podName, prefix))
+	if err != nil {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+// MaxUnavailable caps the number of pods (matching the given label selector)
+// that may be not-Ready at once, counting the candidate pod itself. Percent,
+// when set, takes precedence over Count and is evaluated against the
+// StatefulSet's replica count.
+type MaxUnavailable struct {
+	StatefulSetName  string
+	PodLabelSelector string
+	Namespace        string
+	Count            int
+	Percent          int
+}
+
+// Allow implements Strategy.
+func (m MaxUnavailable) Allow(ctx context.Context, c client.Client, pod *corev1.Pod) (bool, string, error) {
+	limit, err := m.limit(ctx, c)
+	if err != nil {
+		return false, "", err
+	}
+	if limit <= 0 {
+		limit = 1
+	}
+
+	var pods corev1.PodList
+	listOpts := []client.ListOption{client.InNamespace(m.Namespace)}
+	if m.PodLabelSelector != "" {
+		selector, err := labels.Parse(m.PodLabelSelector)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to parse pod label selector: %w", err)
+		}
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
+	}
+	if err := c.List(ctx, &pods, listOpts...); err != nil {
+		return false, "", fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	notReady := 0
+	for _, p := range pods.Items {
+		if p.UID == pod.UID {
+			continue
+		}
+		if !isPodReady(&p) {
+			notReady++
+		}
+	}
+
+	// The candidate itself is already unhealthy, so it counts toward the
+	// budget too.
+	if notReady+1 > limit {
+		return false, fmt.Sprintf("%d pods already unavailable, max-unavailable is %d", notReady, limit), nil
+	}
+
+	return true, "", nil
+}
+
+func (m MaxUnavailable) limit(ctx context.Context, c client.Client) (int, error) {
+	if m.Percent <= 0 {
+		return m.Count, nil
+	}
+
+	var sts appsv1.StatefulSet
+	key := types.NamespacedName{Namespace: m.Namespace, Name: m.StatefulSetName}
+	if err := c.Get(ctx, key, &sts); err != nil {
+		return 0, fmt.Errorf("failed to get StatefulSet for max-unavailable percent: %w", err)
+	}
+
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	limit := int(replicas) * m.Percent / 100
+	if limit < 1 {
+		limit = 1
+	}
+	return limit, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package restart
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOneAtATime_FirstOrdinalAlwaysAllowed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	strategy := OneAtATime{StatefulSetName: "web", Namespace: "default"}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"}}
+
+	allowed, reason, err := strategy.Allow(context.Background(), c, pod)
+	if err != nil {
+		t.Fatalf("Allow() unexpected error: %v", err)
+	}
+	if !allowed {
+		t.Errorf("Allow() = (false, %q), want true for ordinal 0", reason)
+	}
+}
+
+func TestOneAtATime_WaitsForPredecessor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	notReadyPredecessor := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(notReadyPredecessor).Build()
+
+	strategy := OneAtATime{StatefulSetName: "web", Namespace: "default"}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}}
+
+	allowed, reason, err := strategy.Allow(context.Background(), c, pod)
+	if err != nil {
+		t.Fatalf("Allow() unexpected error: %v", err)
+	}
+	if allowed {
+		t.Errorf("Allow() = (true, %q), want false while predecessor is not Ready", reason)
+	}
+}
+
+func TestOneAtATime_ProceedsWhenPredecessorReady(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	readyPredecessor := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(readyPredecessor).Build()
+
+	strategy := OneAtATime{StatefulSetName: "web", Namespace: "default"}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}}
+
+	allowed, _, err := strategy.Allow(context.Background(), c, pod)
+	if err != nil {
+		t.Fatalf("Allow() unexpected error: %v", err)
+	}
+	if !allowed {
+		t.Error("Allow() = false, want true once predecessor is Ready")
+	}
+}
+
+func TestMaxUnavailable_AllowsWithinLimit(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	notReadySibling := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", UID: "web-0", Labels: map[string]string{"app": "web"}},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(notReadySibling).Build()
+
+	strategy := MaxUnavailable{PodLabelSelector: "app=web", Namespace: "default", Count: 2}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", UID: "web-1", Labels: map[string]string{"app": "web"}}}
+
+	allowed, reason, err := strategy.Allow(context.Background(), c, pod)
+	if err != nil {
+		t.Fatalf("Allow() unexpected error: %v", err)
+	}
+	if !allowed {
+		t.Errorf("Allow() = (false, %q), want true with 2 unavailable out of a limit of 2", reason)
+	}
+}
+
+func TestMaxUnavailable_BlocksOverLimit(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	notReadySibling := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", UID: "web-0", Labels: map[string]string{"app": "web"}},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(notReadySibling).Build()
+
+	strategy := MaxUnavailable{PodLabelSelector: "app=web", Namespace: "default", Count: 1}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", UID: "web-1", Labels: map[string]string{"app": "web"}}}
+
+	allowed, reason, err := strategy.Allow(context.Background(), c, pod)
+	if err != nil {
+		t.Fatalf("Allow() unexpected error: %v", err)
+	}
+	if allowed {
+		t.Errorf("Allow() = (true, %q), want false when unavailable siblings plus the candidate exceed the limit", reason)
+	}
+}
+
+func TestMaxUnavailable_PercentOfReplicas(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	replicas := int32(4)
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sts).Build()
+
+	strategy := MaxUnavailable{StatefulSetName: "web", Namespace: "default", Percent: 25}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"}}
+
+	allowed, reason, err := strategy.Allow(context.Background(), c, pod)
+	if err != nil {
+		t.Fatalf("Allow() unexpected error: %v", err)
+	}
+	if !allowed {
+		t.Errorf("Allow() = (false, %q), want true for the candidate alone within a 25%% limit of 4 replicas", reason)
+	}
+}
@@ -0,0 +1,57 @@
+package restart
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultVerifyPollInterval is how often VerifyPodReady re-checks the cache
+// while waiting for a restarted pod to come back up.
+const DefaultVerifyPollInterval = 2 * time.Second
+
+// VerifyConfig configures VerifyPodReady.
+type VerifyConfig struct {
+	// Timeout bounds how long VerifyPodReady waits for the pod to become Ready.
+	Timeout time.Duration
+	// PollInterval, when zero, defaults to DefaultVerifyPollInterval.
+	PollInterval time.Duration
+}
+
+// VerifyPodReady polls c (expected to be a controller-runtime cache-backed
+// client, not a direct API server client) for the pod named key, similar to
+// the Kubernetes e2e suite's WaitForPodRunningInNamespace helper, until a pod
+// with a UID different from previousUID is Ready or cfg.Timeout elapses. It
+// returns whether the pod became Ready and the UID last observed at key, so
+// the caller can key backoff state to the pod that actually failed.
+func VerifyPodReady(ctx context.Context, c client.Client, key client.ObjectKey, previousUID types.UID, cfg VerifyConfig) (ready bool, lastUID types.UID) {
+	interval := cfg.PollInterval
+	if interval <= 0 {
+		interval = DefaultVerifyPollInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var pod corev1.Pod
+		if err := c.Get(ctx, key, &pod); err == nil && pod.UID != "" {
+			lastUID = pod.UID
+			if pod.UID != previousUID && isPodReady(&pod) {
+				return true, lastUID
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, lastUID
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package restart
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestVerifyPodReady_SucceedsOnReadyReplacement(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	replacement := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", UID: types.UID("new")},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(replacement).Build()
+
+	ready, uid := VerifyPodReady(context.Background(), c, client.ObjectKey{Namespace: "default", Name: "web-0"}, types.UID("old"),
+		VerifyConfig{Timeout: time.Second, PollInterval: 10 * time.Millisecond})
+
+	if !ready {
+		t.Fatal("VerifyPodReady() = false, want true for a Ready replacement pod")
+	}
+	if uid != types.UID("new") {
+		t.Errorf("VerifyPodReady() uid = %q, want %q", uid, "new")
+	}
+}
+
+func TestVerifyPodReady_TimesOutWhenStillUnready(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	notReady := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", UID: types.UID("new")},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(notReady).Build()
+
+	ready, uid := VerifyPodReady(context.Background(), c, client.ObjectKey{Namespace: "default", Name: "web-0"}, types.UID("old"),
+		VerifyConfig{Timeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond})
+
+	if ready {
+		t.Error("VerifyPodReady() = true, want false for a pod that never becomes Ready")
+	}
+	if uid != types.UID("new") {
+		t.Errorf("VerifyPodReady() uid = %q, want %q", uid, "new")
+	}
+}